@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"arcanum/internal/config"
+	"arcanum/internal/database"
+	"arcanum/internal/models"
+	"arcanum/internal/services/audit"
+	"arcanum/internal/services/calculator"
+)
+
+// Утилита пересчитывает сохраненные расчеты другой версией алгоритма
+// и выводит JSON отчет о расхождениях. Пример:
+//
+//	go run ./cmd/audit -type pythagoras -to 2
+func main() {
+	calcType := flag.String("type", "", "тип расчета (matrix, pythagoras, ...)")
+	fromVersion := flag.Int("from", 0, "версия для сравнения (0 - сохраненный результат)")
+	toVersion := flag.Int("to", 0, "проверяемая версия (0 - последняя)")
+	flag.Parse()
+
+	if *calcType == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	auditor := audit.NewAuditor(database.NewCalculationRepository(db), calculator.DefaultRegistry())
+	report, err := auditor.Run(context.Background(), models.CalculationType(*calcType), *fromVersion, *toVersion)
+	if err != nil {
+		log.Fatalf("❌ Audit failed: %v", err)
+	}
+
+	log.Printf("✅ Checked %d calculations: %d changed, %d failed", report.Total, report.Changed, report.Failed)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("❌ Failed to write report: %v", err)
+	}
+}
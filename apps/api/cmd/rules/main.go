@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/calculator"
+	"arcanum/internal/services/rules"
+)
+
+// Утилита для работы с правилами интерпретации без запуска сервера.
+// Примеры:
+//
+//	go run ./cmd/rules -export ./rules
+//	go run ./cmd/rules -dir ./rules
+//	go run ./cmd/rules -dir ./rules -eval compatibility -facts '{"spiritualDiff": 2, "karmicTask": 17}' -lang en
+func main() {
+	dir := flag.String("dir", "", "каталог с файлами правил (пусто - встроенные правила)")
+	export := flag.String("export", "", "выгрузить встроенные правила в каталог")
+	eval := flag.String("eval", "", "вычислить набор правил на фактах из -facts")
+	factsJSON := flag.String("facts", "{}", "факты в формате JSON")
+	paramsJSON := flag.String("params", "{}", "текстовые параметры в формате JSON")
+	lang := flag.String("lang", i18n.DefaultLanguage, "язык сообщений")
+	flag.Parse()
+
+	if *export != "" {
+		if err := os.MkdirAll(*export, 0o755); err != nil {
+			log.Fatalf("❌ Failed to create directory: %v", err)
+		}
+		for _, name := range calculator.RuleSetNames() {
+			data, err := calculator.BuiltinRules(name)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(*export, name+".json"), data, 0o644); err != nil {
+				log.Fatalf("❌ Failed to write rules: %v", err)
+			}
+		}
+		log.Printf("✅ Exported %d rule sets to %s", len(calculator.RuleSetNames()), *export)
+		return
+	}
+
+	if *dir != "" {
+		if err := calculator.LoadRules(*dir); err != nil {
+			log.Fatalf("❌ Invalid rules: %v", err)
+		}
+		log.Printf("✅ Rules in %s are valid", *dir)
+	}
+
+	if *eval == "" {
+		return
+	}
+
+	set, ok := calculator.RuleSet(*eval)
+	if !ok {
+		log.Fatalf("❌ Unknown rule set %q, available: %v", *eval, calculator.RuleSetNames())
+	}
+
+	var facts rules.Facts
+	if err := json.Unmarshal([]byte(*factsJSON), &facts); err != nil {
+		log.Fatalf("❌ Invalid facts: %v", err)
+	}
+	var rawParams map[string]string
+	if err := json.Unmarshal([]byte(*paramsJSON), &rawParams); err != nil {
+		log.Fatalf("❌ Invalid params: %v", err)
+	}
+	params := make(i18n.Params, len(rawParams))
+	for name, value := range rawParams {
+		params[name] = i18n.Raw(value)
+	}
+
+	result := set.Evaluate(facts, params)
+	report := struct {
+		Values map[string]interface{} `json:"values"`
+		Texts  map[string][]string    `json:"texts"`
+	}{
+		Values: result.Values(),
+		Texts:  make(map[string][]string),
+	}
+	for list, texts := range result.TextLists() {
+		for _, t := range texts {
+			report.Texts[list] = append(report.Texts[list], i18n.Default().Render(*lang, t))
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("❌ Failed to write result: %v", err)
+	}
+}
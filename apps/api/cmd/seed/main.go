@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+
+	"arcanum/internal/config"
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
+	"arcanum/internal/services/content"
+)
+
+// Утилита загружает тексты арканов из Приложения C в таблицу arcana_interpretations
+// и встроенные тексты психоматрицы на всех языках каталогов в таблицу pythagoras_interpretations.
+// Повторный запуск обновляет записи, кроме опубликованных через админку: у них есть
+// опубликованная ревизия, и такие записи пропускаются. После загрузки сбрасывается
+// кэш Content API. Пример:
+//
+//	go run ./cmd/seed -appendix ../../docs/APPENDIX_C_Full_Interpretations.md
+func main() {
+	appendixPath := flag.String("appendix", "../../docs/APPENDIX_C_Full_Interpretations.md", "путь к Приложению C")
+	flag.Parse()
+
+	file, err := os.Open(*appendixPath)
+	if err != nil {
+		log.Fatalf("❌ Failed to open appendix: %v", err)
+	}
+	defer file.Close()
+
+	items, err := content.ParseAppendix(file)
+	if err != nil {
+		log.Fatalf("❌ Failed to parse appendix: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	redis, err := database.NewRedis(&cfg.Redis)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to Redis: %v", err)
+	}
+	defer redis.Close()
+
+	contentRepo := database.NewContentRepository(db)
+	revisionRepo := database.NewContentRevisionRepository(db)
+	ctx := context.Background()
+
+	published, err := revisionRepo.PublishedKeys(ctx, models.ContentTypeArcana, content.AppendixLanguage)
+	if err != nil {
+		log.Fatalf("❌ Failed to load published revisions: %v", err)
+	}
+
+	imported := 0
+	for _, item := range items {
+		if published[strconv.Itoa(item.ArcanaNumber)] {
+			continue
+		}
+		if err := contentRepo.UpsertArcana(ctx, item); err != nil {
+			log.Fatalf("❌ Failed to save arcana %d: %v", item.ArcanaNumber, err)
+		}
+		imported++
+	}
+
+	log.Printf("✅ Imported %d arcana interpretations (%s), skipped %d edited in CMS", imported, content.AppendixLanguage, len(items)-imported)
+
+	for _, lang := range i18n.Languages() {
+		published, err := revisionRepo.PublishedKeys(ctx, models.ContentTypePythagoras, lang)
+		if err != nil {
+			log.Fatalf("❌ Failed to load published revisions: %v", err)
+		}
+
+		sets := calculator.BuiltinPythagorasTexts(lang)
+		imported := 0
+		for _, set := range sets {
+			if published[set.Element+":"+set.Key] {
+				continue
+			}
+			item := &models.PythagorasElementInterpretation{
+				Element:  set.Element,
+				Key:      set.Key,
+				Language: lang,
+				Name:     set.Name,
+				Texts:    set.Texts,
+			}
+			if err := contentRepo.UpsertPythagoras(ctx, item); err != nil {
+				log.Fatalf("❌ Failed to save pythagoras %s:%s (%s): %v", set.Element, set.Key, lang, err)
+			}
+			imported++
+		}
+
+		log.Printf("✅ Imported %d pythagoras interpretations (%s), skipped %d edited in CMS", imported, lang, len(sets)-imported)
+	}
+
+	// Сбрасываем кэш, чтобы Content API не отдавал старые тексты и ETag
+	if err := content.NewCache(redis).Invalidate(ctx); err != nil {
+		log.Fatalf("❌ Failed to invalidate content cache: %v", err)
+	}
+}
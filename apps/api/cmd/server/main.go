@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
+
+	"arcanum/internal/config"
+	"arcanum/internal/database"
+	"arcanum/internal/handlers"
+	"arcanum/internal/middleware"
+	"arcanum/internal/services/calculator"
+	"arcanum/internal/services/content"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// shutdownTimeout - время на завершение активных запросов при остановке
+	shutdownTimeout = 15 * time.Second
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+
+	if cfg.App.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	calculator.SetMinBirthYear(cfg.App.MinBirthYear)
+	if cfg.App.RulesDir != "" {
+		if err := calculator.LoadRules(cfg.App.RulesDir); err != nil {
+			log.Fatalf("❌ Failed to load rules: %v", err)
+		}
+		log.Printf("📐 Loaded interpretation rules from %s", cfg.App.RulesDir)
+	}
+	if err := handlers.RegisterValidators(); err != nil {
+		log.Fatalf("❌ Failed to register validators: %v", err)
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+
+	redis, err := database.NewRedis(&cfg.Redis)
+	if err != nil {
+		db.Close()
+		log.Fatalf("❌ Failed to connect to Redis: %v", err)
+	}
+
+	router := setupRouter(cfg, db, redis)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.App.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Запускаем сервер в отдельной горутине, чтобы дождаться сигнала остановки
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("🚀 Server listening on :%s (%s)", cfg.App.Port, cfg.App.Environment)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Printf("❌ Server error: %v", err)
+		}
+	case sig := <-quit:
+		log.Printf("⏳ Received %s, shutting down...", sig)
+	}
+
+	// Дожидаемся завершения активных запросов
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("❌ Graceful shutdown failed: %v", err)
+	}
+
+	if err := redis.Close(); err != nil {
+		log.Printf("❌ Failed to close Redis: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		log.Printf("❌ Failed to close database: %v", err)
+	}
+
+	log.Println("✅ Server stopped")
+}
+
+// setupRouter регистрирует все маршруты API
+func setupRouter(cfg *config.Config, db *database.Database, redis *database.RedisClient) *gin.Engine {
+	// Репозитории
+	userRepo := database.NewUserRepository(db)
+	refreshTokenRepo := database.NewRefreshTokenRepository(db)
+	calcRepo := database.NewCalculationRepository(db)
+	contentRepo := database.NewContentRepository(db)
+	contentRevisionRepo := database.NewContentRevisionRepository(db)
+
+	// Сервисы
+	contentCache := content.NewCache(redis)
+	cms := content.NewCMS(contentRevisionRepo, contentCache)
+	contentTexts := content.NewTexts(contentRepo, contentCache)
+
+	// Обработчики
+	healthHandler := handlers.NewHealthHandler(db, redis)
+	authHandler := handlers.NewAuthHandler(userRepo, refreshTokenRepo, cfg)
+	registry := calculator.DefaultRegistry()
+	userHandler := handlers.NewUserHandler(userRepo, registry)
+	calculationHandler := handlers.NewCalculationHandler(registry)
+	storageHandler := handlers.NewCalculationStorageHandler(calcRepo, registry)
+	batchHandler := handlers.NewBatchHandler(registry, cfg.Batch)
+	contentHandler := handlers.NewContentHandler(contentRepo, contentCache, contentTexts)
+	adminContentHandler := handlers.NewAdminContentHandler(cms)
+	adminMatrixHandler := handlers.NewAdminMatrixHandler()
+
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery())
+	router.Use(middleware.CORS(cfg.CORS.AllowedOrigins))
+
+	router.GET("/health", healthHandler.Health)
+
+	jwtAuth := middleware.JWTAuth(&cfg.JWT)
+	optionalAuth := middleware.OptionalJWTAuth(&cfg.JWT)
+	premiumStatus := middleware.LoadPremiumStatus(userRepo)
+	language := middleware.Language(userRepo)
+
+	api := router.Group("/api/" + cfg.App.APIVersion)
+	api.Use(middleware.RateLimiter(redis, cfg.RateLimit.RequestsPerMinute))
+	api.Use(middleware.ContentTexts(contentTexts))
+
+	// Аутентификация
+	auth := api.Group("/auth")
+	{
+		auth.POST("/register", authHandler.Register)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/logout", authHandler.Logout)
+		auth.POST("/refresh", authHandler.Refresh)
+	}
+
+	// Профиль пользователя
+	users := api.Group("/users", jwtAuth)
+	{
+		users.GET("/me", userHandler.GetProfile)
+		users.PUT("/me", userHandler.UpdateProfile)
+		users.GET("/me/today", language, userHandler.GetToday)
+	}
+
+	// Сохраненные расчеты
+	calculations := api.Group("/calculations", jwtAuth, language)
+	{
+		calculations.POST("", storageHandler.SaveCalculation)
+		calculations.GET("", storageHandler.GetCalculations)
+		calculations.GET("/:id", storageHandler.GetCalculation)
+		calculations.DELETE("/:id", storageHandler.DeleteCalculation)
+		calculations.POST("/:id/recompute", storageHandler.RecomputeCalculation)
+	}
+
+	// Расчеты
+	calculate := api.Group("/calculate", optionalAuth, language)
+	{
+		calculate.POST("/matrix", calculationHandler.CalculateMatrix)
+		calculate.POST("/pythagoras", calculationHandler.CalculatePythagoras)
+		calculate.POST("/forecasts", premiumStatus, calculationHandler.CalculateForecasts)
+		calculate.POST("/age-timeline", calculationHandler.CalculateAgeTimeline)
+		calculate.POST("/health-map", calculationHandler.CalculateHealthMap)
+		calculate.POST("/name-number", calculationHandler.CalculateNameNumber)
+		calculate.POST("/day", calculationHandler.CalculateDay)
+		calculate.POST("/batch", jwtAuth, premiumStatus, batchHandler.CalculateBatch)
+	}
+
+	// Premium расчеты
+	premium := calculate.Group("", jwtAuth, premiumStatus, middleware.RequirePremium())
+	{
+		premium.POST("/compatibility", calculationHandler.CalculateCompatibility)
+		premium.POST("/compatibility/group", calculationHandler.CalculateGroupCompatibility)
+		premium.POST("/child-role", calculationHandler.CalculateChildRole)
+		premium.POST("/favorable-dates", calculationHandler.CalculateFavorableDates)
+	}
+
+	// Справочный контент
+	contentGroup := api.Group("/content")
+	{
+		contentGroup.GET("/arcana/:id", contentHandler.GetArcana)
+		contentGroup.GET("/interpretations", contentHandler.GetInterpretations)
+		contentGroup.GET("/pythagoras", contentHandler.GetPythagoras)
+		contentGroup.GET("/compatibility", contentHandler.GetCompatibilityTexts)
+	}
+
+	// Редактирование контента (только администраторы)
+	adminContent := api.Group("/admin/content", jwtAuth, middleware.RequireAdmin(userRepo))
+	{
+		adminContent.GET("/revisions", adminContentHandler.GetReviewQueue)
+		adminContent.GET("/revisions/:id", adminContentHandler.GetRevision)
+		adminContent.POST("/revisions/:id/submit", adminContentHandler.SubmitRevision)
+		adminContent.POST("/revisions/:id/reject", adminContentHandler.RejectRevision)
+		adminContent.POST("/revisions/:id/publish", adminContentHandler.PublishRevision)
+		adminContent.POST("/revisions/:id/rollback", adminContentHandler.RollbackRevision)
+		adminContent.GET("/items/:type/:key/:lang/revisions", adminContentHandler.GetHistory)
+		adminContent.POST("/items/:type/:key/:lang/revisions", adminContentHandler.SaveRevision)
+	}
+
+	// Обратный поиск по матрице (только администраторы)
+	adminMatrix := api.Group("/admin/matrix", jwtAuth, middleware.RequireAdmin(userRepo), language)
+	{
+		adminMatrix.GET("/search", adminMatrixHandler.SearchMatrix)
+	}
+
+	return router
+}
@@ -10,19 +10,23 @@ import (
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Stripe   StripeConfig
-	Logging  LoggingConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	CORS      CORSConfig
+	RateLimit RateLimitConfig
+	Batch     BatchConfig
+	Stripe    StripeConfig
+	Logging   LoggingConfig
 }
 
 type AppConfig struct {
-	Environment string
-	Port        string
-	APIVersion  string
+	Environment  string
+	Port         string
+	APIVersion   string
+	MinBirthYear int
+	RulesDir     string
 }
 
 type DatabaseConfig struct {
@@ -49,6 +53,16 @@ type CORSConfig struct {
 	AllowedOrigins string
 }
 
+type RateLimitConfig struct {
+	RequestsPerMinute int
+}
+
+type BatchConfig struct {
+	MaxRowsFree    int
+	MaxRowsPremium int
+	MaxBodyBytes   int64
+}
+
 type StripeConfig struct {
 	SecretKey     string
 	WebhookSecret string
@@ -65,9 +79,11 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		App: AppConfig{
-			Environment: getEnv("APP_ENV", "development"),
-			Port:        getEnv("APP_PORT", "3001"),
-			APIVersion:  getEnv("API_VERSION", "v1"),
+			Environment:  getEnv("APP_ENV", "development"),
+			Port:         getEnv("APP_PORT", "3001"),
+			APIVersion:   getEnv("API_VERSION", "v1"),
+			MinBirthYear: getEnvAsInt("MIN_BIRTH_YEAR", 1900),
+			RulesDir:     getEnv("RULES_DIR", ""),
 		},
 		Database: DatabaseConfig{
 			URL:                getEnv("DATABASE_URL", ""),
@@ -89,6 +105,14 @@ func Load() (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"),
 		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
+		},
+		Batch: BatchConfig{
+			MaxRowsFree:    getEnvAsInt("BATCH_MAX_ROWS_FREE", 20),
+			MaxRowsPremium: getEnvAsInt("BATCH_MAX_ROWS_PREMIUM", 1000),
+			MaxBodyBytes:   int64(getEnvAsInt("BATCH_MAX_BODY_MB", 10)) << 20,
+		},
 		Stripe: StripeConfig{
 			SecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
 			WebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
@@ -24,8 +24,8 @@ func NewCalculationRepository(db *Database) *CalculationRepository {
 // Create создает новый расчет
 func (r *CalculationRepository) Create(ctx context.Context, calc *models.Calculation) error {
 	query := `
-		INSERT INTO calculations (id, user_id, type, input_data, result_data, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO calculations (id, user_id, type, algorithm_version, language, input_data, result_data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	inputDataJSON, err := json.Marshal(calc.InputData)
@@ -42,6 +42,8 @@ func (r *CalculationRepository) Create(ctx context.Context, calc *models.Calcula
 		calc.ID,
 		calc.UserID,
 		calc.Type,
+		calc.AlgorithmVersion,
+		calc.Language,
 		inputDataJSON,
 		resultDataJSON,
 		calc.CreatedAt,
@@ -57,7 +59,7 @@ func (r *CalculationRepository) FindByUserID(ctx context.Context, userID string,
 
 	if calcType != nil {
 		query = `
-			SELECT id, user_id, type, input_data, result_data, created_at
+			SELECT id, user_id, type, algorithm_version, language, input_data, result_data, created_at
 			FROM calculations
 			WHERE user_id = $1 AND type = $2
 			ORDER BY created_at DESC
@@ -65,7 +67,7 @@ func (r *CalculationRepository) FindByUserID(ctx context.Context, userID string,
 		args = []interface{}{userID, *calcType}
 	} else {
 		query = `
-			SELECT id, user_id, type, input_data, result_data, created_at
+			SELECT id, user_id, type, algorithm_version, language, input_data, result_data, created_at
 			FROM calculations
 			WHERE user_id = $1
 			ORDER BY created_at DESC
@@ -88,6 +90,8 @@ func (r *CalculationRepository) FindByUserID(ctx context.Context, userID string,
 			&calc.ID,
 			&calc.UserID,
 			&calc.Type,
+			&calc.AlgorithmVersion,
+			&calc.Language,
 			&inputDataJSON,
 			&resultDataJSON,
 			&calc.CreatedAt,
@@ -117,7 +121,7 @@ func (r *CalculationRepository) FindByUserID(ctx context.Context, userID string,
 // FindByID находит расчет по ID
 func (r *CalculationRepository) FindByID(ctx context.Context, id string) (*models.Calculation, error) {
 	query := `
-		SELECT id, user_id, type, input_data, result_data, created_at
+		SELECT id, user_id, type, algorithm_version, language, input_data, result_data, created_at
 		FROM calculations
 		WHERE id = $1
 	`
@@ -129,6 +133,8 @@ func (r *CalculationRepository) FindByID(ctx context.Context, id string) (*model
 		&calc.ID,
 		&calc.UserID,
 		&calc.Type,
+		&calc.AlgorithmVersion,
+		&calc.Language,
 		&inputDataJSON,
 		&resultDataJSON,
 		&calc.CreatedAt,
@@ -152,6 +158,60 @@ func (r *CalculationRepository) FindByID(ctx context.Context, id string) (*model
 	return calc, nil
 }
 
+// ListByType возвращает расчеты указанного типа постранично, упорядоченные по ID.
+// afterID - ID последнего расчета предыдущей страницы (пустой для первой страницы)
+func (r *CalculationRepository) ListByType(ctx context.Context, calcType models.CalculationType, afterID string, limit int) ([]*models.Calculation, error) {
+	query := `
+		SELECT id, user_id, type, algorithm_version, language, input_data, result_data, created_at
+		FROM calculations
+		WHERE type = $1 AND ($2 = '' OR id > $2::uuid)
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query, calcType, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calculations []*models.Calculation
+	for rows.Next() {
+		calc := &models.Calculation{}
+		var inputDataJSON, resultDataJSON []byte
+
+		err := rows.Scan(
+			&calc.ID,
+			&calc.UserID,
+			&calc.Type,
+			&calc.AlgorithmVersion,
+			&calc.Language,
+			&inputDataJSON,
+			&resultDataJSON,
+			&calc.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Парсим JSON данные
+		if err := json.Unmarshal(inputDataJSON, &calc.InputData); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(resultDataJSON, &calc.ResultData); err != nil {
+			return nil, err
+		}
+
+		calculations = append(calculations, calc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return calculations, nil
+}
+
 // Delete удаляет расчет
 func (r *CalculationRepository) Delete(ctx context.Context, id, userID string) error {
 	query := `
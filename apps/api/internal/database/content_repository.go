@@ -0,0 +1,309 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"arcanum/internal/models"
+)
+
+var (
+	ErrInterpretationNotFound = errors.New("interpretation not found")
+)
+
+type ContentRepository struct {
+	db *Database
+}
+
+func NewContentRepository(db *Database) *ContentRepository {
+	return &ContentRepository{db: db}
+}
+
+const arcanaInterpretationColumns = `
+	arcana_number, language, title,
+	COALESCE(short_description, ''), COALESCE(full_description, ''),
+	plus_aspects, minus_aspects,
+	COALESCE(love_aspect, ''), COALESCE(money_aspect, ''),
+	COALESCE(health_aspect, ''), COALESCE(child_aspect, ''),
+	recommendations, updated_at
+`
+
+// FindArcana находит интерпретацию аркана на указанном языке
+func (r *ContentRepository) FindArcana(ctx context.Context, number int, language string) (*models.ArcanaInterpretation, error) {
+	query := `
+		SELECT ` + arcanaInterpretationColumns + `
+		FROM arcana_interpretations
+		WHERE arcana_number = $1 AND language = $2
+	`
+
+	item, err := scanArcanaInterpretation(r.db.DB.QueryRowContext(ctx, query, number, language))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInterpretationNotFound
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ListArcana возвращает интерпретации всех арканов на указанном языке
+func (r *ContentRepository) ListArcana(ctx context.Context, language string) ([]*models.ArcanaInterpretation, error) {
+	query := `
+		SELECT ` + arcanaInterpretationColumns + `
+		FROM arcana_interpretations
+		WHERE language = $1
+		ORDER BY arcana_number
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query, language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.ArcanaInterpretation, 0)
+	for rows.Next() {
+		item, err := scanArcanaInterpretation(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// UpsertArcana создает или обновляет интерпретацию аркана по (arcana_number, language)
+func (r *ContentRepository) UpsertArcana(ctx context.Context, item *models.ArcanaInterpretation) error {
+	return upsertArcana(ctx, r.db.DB, item)
+}
+
+// ListPythagoras возвращает интерпретации элементов психоматрицы на указанном языке
+func (r *ContentRepository) ListPythagoras(ctx context.Context, language string) ([]*models.PythagorasElementInterpretation, error) {
+	query := `
+		SELECT element, element_key, language, name, texts, updated_at
+		FROM pythagoras_interpretations
+		WHERE language = $1
+		ORDER BY element, id
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query, language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.PythagorasElementInterpretation, 0)
+	for rows.Next() {
+		item := &models.PythagorasElementInterpretation{}
+		var textsJSON []byte
+
+		err := rows.Scan(
+			&item.Element,
+			&item.Key,
+			&item.Language,
+			&item.Name,
+			&textsJSON,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(textsJSON, &item.Texts); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// UpsertPythagoras создает или обновляет интерпретацию элемента психоматрицы
+func (r *ContentRepository) UpsertPythagoras(ctx context.Context, item *models.PythagorasElementInterpretation) error {
+	return upsertPythagoras(ctx, r.db.DB, item)
+}
+
+// ListCompatibilityTexts возвращает опубликованные тексты совместимости на всех языках
+func (r *ContentRepository) ListCompatibilityTexts(ctx context.Context) ([]*models.CompatibilityText, error) {
+	query := `
+		SELECT text_key, language, text, updated_at
+		FROM compatibility_texts
+		ORDER BY text_key, language
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.CompatibilityText, 0)
+	for rows.Next() {
+		item := &models.CompatibilityText{}
+		if err := rows.Scan(&item.Key, &item.Language, &item.Text, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// querier - общий интерфейс *sql.DB и *sql.Tx
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func upsertArcana(ctx context.Context, q querier, item *models.ArcanaInterpretation) error {
+	query := `
+		INSERT INTO arcana_interpretations (
+			arcana_number, language, title, short_description, full_description,
+			plus_aspects, minus_aspects, love_aspect, money_aspect, health_aspect,
+			child_aspect, recommendations
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (arcana_number, language) DO UPDATE SET
+			title = EXCLUDED.title,
+			short_description = EXCLUDED.short_description,
+			full_description = EXCLUDED.full_description,
+			plus_aspects = EXCLUDED.plus_aspects,
+			minus_aspects = EXCLUDED.minus_aspects,
+			love_aspect = EXCLUDED.love_aspect,
+			money_aspect = EXCLUDED.money_aspect,
+			health_aspect = EXCLUDED.health_aspect,
+			child_aspect = EXCLUDED.child_aspect,
+			recommendations = EXCLUDED.recommendations
+		RETURNING updated_at
+	`
+
+	plusJSON, err := marshalStrings(item.Plus)
+	if err != nil {
+		return err
+	}
+	minusJSON, err := marshalStrings(item.Minus)
+	if err != nil {
+		return err
+	}
+	recommendationsJSON, err := marshalStrings(item.Recommendations)
+	if err != nil {
+		return err
+	}
+
+	return q.QueryRowContext(ctx, query,
+		item.ArcanaNumber,
+		item.Language,
+		item.Title,
+		item.ShortDescription,
+		item.FullDescription,
+		plusJSON,
+		minusJSON,
+		item.Love,
+		item.Money,
+		item.Health,
+		item.Child,
+		recommendationsJSON,
+	).Scan(&item.UpdatedAt)
+}
+
+func upsertPythagoras(ctx context.Context, q querier, item *models.PythagorasElementInterpretation) error {
+	query := `
+		INSERT INTO pythagoras_interpretations (element, element_key, language, name, texts)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (element, element_key, language) DO UPDATE SET
+			name = EXCLUDED.name,
+			texts = EXCLUDED.texts
+		RETURNING updated_at
+	`
+
+	textsJSON, err := json.Marshal(item.Texts)
+	if err != nil {
+		return err
+	}
+
+	return q.QueryRowContext(ctx, query,
+		item.Element,
+		item.Key,
+		item.Language,
+		item.Name,
+		textsJSON,
+	).Scan(&item.UpdatedAt)
+}
+
+func upsertCompatibilityText(ctx context.Context, q querier, item *models.CompatibilityText) error {
+	query := `
+		INSERT INTO compatibility_texts (text_key, language, text)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (text_key, language) DO UPDATE SET
+			text = EXCLUDED.text
+		RETURNING updated_at
+	`
+
+	return q.QueryRowContext(ctx, query, item.Key, item.Language, item.Text).Scan(&item.UpdatedAt)
+}
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanArcanaInterpretation(row rowScanner) (*models.ArcanaInterpretation, error) {
+	item := &models.ArcanaInterpretation{}
+	var plusJSON, minusJSON, recommendationsJSON []byte
+
+	err := row.Scan(
+		&item.ArcanaNumber,
+		&item.Language,
+		&item.Title,
+		&item.ShortDescription,
+		&item.FullDescription,
+		&plusJSON,
+		&minusJSON,
+		&item.Love,
+		&item.Money,
+		&item.Health,
+		&item.Child,
+		&recommendationsJSON,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Парсим JSON данные
+	if err := json.Unmarshal(plusJSON, &item.Plus); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(minusJSON, &item.Minus); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(recommendationsJSON, &item.Recommendations); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// marshalStrings сериализует список строк в JSONB (nil сохраняется как пустой массив)
+func marshalStrings(values []string) ([]byte, error) {
+	if values == nil {
+		values = []string{}
+	}
+	return json.Marshal(values)
+}
@@ -0,0 +1,302 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"arcanum/internal/models"
+)
+
+var (
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrInvalidRevisionStatus = errors.New("invalid revision status for this action")
+)
+
+type ContentRevisionRepository struct {
+	db *Database
+}
+
+func NewContentRevisionRepository(db *Database) *ContentRevisionRepository {
+	return &ContentRevisionRepository{db: db}
+}
+
+const contentRevisionColumns = `
+	id, content_type, content_key, language, revision, status, data,
+	COALESCE(comment, ''), author_id, created_at, submitted_at, published_by, published_at
+`
+
+// Create сохраняет новую ревизию со следующим порядковым номером
+func (r *ContentRevisionRepository) Create(ctx context.Context, rev *models.ContentRevision) error {
+	query := `
+		INSERT INTO content_revisions (content_type, content_key, language, revision, status, data, comment, author_id)
+		SELECT $1, $2, $3, COALESCE(MAX(revision), 0) + 1, $4::content_status, $5::jsonb, $6, $7::uuid
+		FROM content_revisions
+		WHERE content_type = $1 AND content_key = $2 AND language = $3
+		RETURNING id, revision, created_at
+	`
+
+	return r.db.DB.QueryRowContext(ctx, query,
+		rev.ContentType,
+		rev.ContentKey,
+		rev.Language,
+		rev.Status,
+		[]byte(rev.Data),
+		rev.Comment,
+		rev.AuthorID,
+	).Scan(&rev.ID, &rev.Revision, &rev.CreatedAt)
+}
+
+// FindByID находит ревизию по ID
+func (r *ContentRevisionRepository) FindByID(ctx context.Context, id string) (*models.ContentRevision, error) {
+	query := `
+		SELECT ` + contentRevisionColumns + `
+		FROM content_revisions
+		WHERE id = $1
+	`
+
+	rev, err := scanContentRevision(r.db.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// ListByContent возвращает историю ревизий единицы контента, начиная с последней
+func (r *ContentRevisionRepository) ListByContent(ctx context.Context, contentType models.ContentType, key, language string) ([]*models.ContentRevision, error) {
+	query := `
+		SELECT ` + contentRevisionColumns + `
+		FROM content_revisions
+		WHERE content_type = $1 AND content_key = $2 AND language = $3
+		ORDER BY revision DESC
+	`
+
+	return r.list(ctx, query, contentType, key, language)
+}
+
+// ListByStatus возвращает ревизии в указанном статусе (например, очередь на проверку)
+func (r *ContentRevisionRepository) ListByStatus(ctx context.Context, status models.ContentStatus) ([]*models.ContentRevision, error) {
+	query := `
+		SELECT ` + contentRevisionColumns + `
+		FROM content_revisions
+		WHERE status = $1
+		ORDER BY created_at
+	`
+
+	return r.list(ctx, query, status)
+}
+
+// PublishedKeys возвращает ключи контента типа contentType на языке language,
+// у которых есть опубликованная ревизия
+func (r *ContentRevisionRepository) PublishedKeys(ctx context.Context, contentType models.ContentType, language string) (map[string]bool, error) {
+	query := `
+		SELECT content_key
+		FROM content_revisions
+		WHERE content_type = $1 AND language = $2 AND status = $3
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query, contentType, language, models.ContentStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys[key] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Submit переводит черновик на проверку
+func (r *ContentRevisionRepository) Submit(ctx context.Context, id string) error {
+	query := `
+		UPDATE content_revisions
+		SET status = $1, submitted_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = $3
+	`
+
+	return r.transition(ctx, query, id, models.ContentStatusReview, id, models.ContentStatusDraft)
+}
+
+// Reject возвращает ревизию с проверки в черновики
+func (r *ContentRevisionRepository) Reject(ctx context.Context, id string) error {
+	query := `
+		UPDATE content_revisions
+		SET status = $1, submitted_at = NULL
+		WHERE id = $2 AND status = $3
+	`
+
+	return r.transition(ctx, query, id, models.ContentStatusDraft, id, models.ContentStatusReview)
+}
+
+// Publish публикует ревизию в статусе from: предыдущая опубликованная ревизия
+// архивируется, а данные ревизии записываются в таблицу опубликованного контента.
+// Все изменения выполняются в одной транзакции
+func (r *ContentRevisionRepository) Publish(ctx context.Context, id, publisherID string, from models.ContentStatus) (*models.ContentRevision, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ` + contentRevisionColumns + `
+		FROM content_revisions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	rev, err := scanContentRevision(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	if rev.Status != from {
+		return nil, ErrInvalidRevisionStatus
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE content_revisions
+		SET status = $1
+		WHERE content_type = $2 AND content_key = $3 AND language = $4 AND status = $5
+	`, models.ContentStatusArchived, rev.ContentType, rev.ContentKey, rev.Language, models.ContentStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE content_revisions
+		SET status = $1, published_by = $2, published_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+		RETURNING status, published_by, published_at
+	`, models.ContentStatusPublished, publisherID, id).Scan(&rev.Status, &rev.PublishedBy, &rev.PublishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyRevision(ctx, tx, rev); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// applyRevision записывает данные ревизии в таблицу опубликованного контента
+func applyRevision(ctx context.Context, q querier, rev *models.ContentRevision) error {
+	switch rev.ContentType {
+	case models.ContentTypeArcana:
+		var item models.ArcanaInterpretation
+		if err := json.Unmarshal(rev.Data, &item); err != nil {
+			return err
+		}
+		return upsertArcana(ctx, q, &item)
+	case models.ContentTypePythagoras:
+		var item models.PythagorasElementInterpretation
+		if err := json.Unmarshal(rev.Data, &item); err != nil {
+			return err
+		}
+		return upsertPythagoras(ctx, q, &item)
+	case models.ContentTypeCompatibility:
+		var item models.CompatibilityText
+		if err := json.Unmarshal(rev.Data, &item); err != nil {
+			return err
+		}
+		return upsertCompatibilityText(ctx, q, &item)
+	default:
+		return fmt.Errorf("unknown content type: %s", rev.ContentType)
+	}
+}
+
+// transition выполняет смену статуса и проверяет, что ревизия была в ожидаемом статусе
+func (r *ContentRevisionRepository) transition(ctx context.Context, query, id string, args ...interface{}) error {
+	result, err := r.db.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrInvalidRevisionStatus
+	}
+
+	return nil
+}
+
+func (r *ContentRevisionRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.ContentRevision, error) {
+	rows, err := r.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.ContentRevision, 0)
+	for rows.Next() {
+		rev, err := scanContentRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func scanContentRevision(row rowScanner) (*models.ContentRevision, error) {
+	rev := &models.ContentRevision{}
+	var data []byte
+
+	err := row.Scan(
+		&rev.ID,
+		&rev.ContentType,
+		&rev.ContentKey,
+		&rev.Language,
+		&rev.Revision,
+		&rev.Status,
+		&data,
+		&rev.Comment,
+		&rev.AuthorID,
+		&rev.CreatedAt,
+		&rev.SubmittedAt,
+		&rev.PublishedBy,
+		&rev.PublishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rev.Data = json.RawMessage(data)
+	return rev, nil
+}
@@ -7,8 +7,9 @@ import (
 	"log"
 	"time"
 
+	"arcanum/internal/config"
+
 	_ "github.com/lib/pq"
-	"github.com/akhludeyev/arcanum/api/internal/config"
 )
 
 type Database struct {
@@ -6,8 +6,9 @@ import (
 	"log"
 	"time"
 
+	"arcanum/internal/config"
+
 	"github.com/redis/go-redis/v9"
-	"github.com/akhludeyev/arcanum/api/internal/config"
 )
 
 type RedisClient struct {
@@ -25,8 +25,8 @@ func NewUserRepository(db *Database) *UserRepository {
 // Create создает нового пользователя
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, name, is_premium, premium_expires_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO users (id, email, password_hash, name, role, language, birth_date, timezone, is_premium, premium_expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, to_date($7, 'DD.MM.YYYY'), $8, $9, $10, $11, $12)
 	`
 
 	_, err := r.db.DB.ExecContext(ctx, query,
@@ -34,6 +34,10 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		user.Email,
 		user.PasswordHash,
 		user.Name,
+		user.Role,
+		user.Language,
+		user.BirthDate,
+		user.Timezone,
 		user.IsPremium,
 		user.PremiumExpiresAt,
 		user.CreatedAt,
@@ -54,7 +58,8 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 // FindByEmail находит пользователя по email
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, is_premium, premium_expires_at, created_at, updated_at
+		SELECT id, email, password_hash, name, role, language, to_char(birth_date, 'DD.MM.YYYY'), timezone,
+			is_premium, premium_expires_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -65,6 +70,10 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.Role,
+		&user.Language,
+		&user.BirthDate,
+		&user.Timezone,
 		&user.IsPremium,
 		&user.PremiumExpiresAt,
 		&user.CreatedAt,
@@ -84,7 +93,8 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 // FindByID находит пользователя по ID
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, is_premium, premium_expires_at, created_at, updated_at
+		SELECT id, email, password_hash, name, role, language, to_char(birth_date, 'DD.MM.YYYY'), timezone,
+			is_premium, premium_expires_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -95,6 +105,10 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.Role,
+		&user.Language,
+		&user.BirthDate,
+		&user.Timezone,
 		&user.IsPremium,
 		&user.PremiumExpiresAt,
 		&user.CreatedAt,
@@ -115,14 +129,18 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users
-		SET name = $1, is_premium = $2, premium_expires_at = $3, updated_at = $4
-		WHERE id = $5
+		SET name = $1, language = $2, birth_date = to_date($3, 'DD.MM.YYYY'), timezone = $4,
+			is_premium = $5, premium_expires_at = $6, updated_at = $7
+		WHERE id = $8
 	`
 
 	user.UpdatedAt = time.Now()
 
 	result, err := r.db.DB.ExecContext(ctx, query,
 		user.Name,
+		user.Language,
+		user.BirthDate,
+		user.Timezone,
 		user.IsPremium,
 		user.PremiumExpiresAt,
 		user.UpdatedAt,
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"arcanum/internal/database"
+	"arcanum/internal/models"
+	"arcanum/internal/services/content"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminContentHandler struct {
+	cms *content.CMS
+}
+
+func NewAdminContentHandler(cms *content.CMS) *AdminContentHandler {
+	return &AdminContentHandler{
+		cms: cms,
+	}
+}
+
+type SaveRevisionRequest struct {
+	Data    json.RawMessage `json:"data" binding:"required"`
+	Comment string          `json:"comment"`
+}
+
+// SaveRevision godoc
+// @Summary Save content draft
+// @Description Create a new draft revision of an arcana or Pythagoras interpretation or a compatibility text. Key is the arcana number (1-22) for arcana, element:key (e.g. cell:character) for pythagoras and the message key without the compatibility. prefix (e.g. strength.spiritual) for compatibility
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type (arcana, pythagoras, compatibility)"
+// @Param key path string true "Content key"
+// @Param lang path string true "Language"
+// @Param request body SaveRevisionRequest true "Revision data"
+// @Success 201 {object} RevisionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/content/items/{type}/{key}/{lang}/revisions [post]
+func (h *AdminContentHandler) SaveRevision(c *gin.Context) {
+	var req SaveRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	rev, err := h.cms.SaveDraft(
+		c.Request.Context(),
+		models.ContentType(c.Param("type")),
+		c.Param("key"),
+		c.Param("lang"),
+		req.Data,
+		req.Comment,
+		c.GetString("userID"),
+	)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, RevisionResponse{
+		Success: true,
+		Data:    rev,
+	})
+}
+
+// GetHistory godoc
+// @Summary Get content revision history
+// @Description Get all revisions of an interpretation, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type (arcana, pythagoras, compatibility)"
+// @Param key path string true "Content key"
+// @Param lang path string true "Language"
+// @Success 200 {object} RevisionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/content/items/{type}/{key}/{lang}/revisions [get]
+func (h *AdminContentHandler) GetHistory(c *gin.Context) {
+	revisions, err := h.cms.History(
+		c.Request.Context(),
+		models.ContentType(c.Param("type")),
+		c.Param("key"),
+		c.Param("lang"),
+	)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RevisionListResponse{
+		Success: true,
+		Data:    revisions,
+	})
+}
+
+// GetReviewQueue godoc
+// @Summary Get revisions awaiting review
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} RevisionListResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions [get]
+func (h *AdminContentHandler) GetReviewQueue(c *gin.Context) {
+	revisions, err := h.cms.ReviewQueue(c.Request.Context())
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RevisionListResponse{
+		Success: true,
+		Data:    revisions,
+	})
+}
+
+// GetRevision godoc
+// @Summary Get content revision
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Revision ID"
+// @Success 200 {object} RevisionResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions/{id} [get]
+func (h *AdminContentHandler) GetRevision(c *gin.Context) {
+	h.revisionAction(c, http.StatusOK, func(id string) (*models.ContentRevision, error) {
+		return h.cms.Revision(c.Request.Context(), id)
+	})
+}
+
+// SubmitRevision godoc
+// @Summary Submit draft for review
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Revision ID"
+// @Success 200 {object} RevisionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions/{id}/submit [post]
+func (h *AdminContentHandler) SubmitRevision(c *gin.Context) {
+	h.revisionAction(c, http.StatusOK, func(id string) (*models.ContentRevision, error) {
+		return h.cms.Submit(c.Request.Context(), id)
+	})
+}
+
+// RejectRevision godoc
+// @Summary Return revision from review to drafts
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Revision ID"
+// @Success 200 {object} RevisionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions/{id}/reject [post]
+func (h *AdminContentHandler) RejectRevision(c *gin.Context) {
+	h.revisionAction(c, http.StatusOK, func(id string) (*models.ContentRevision, error) {
+		return h.cms.Reject(c.Request.Context(), id)
+	})
+}
+
+// PublishRevision godoc
+// @Summary Publish reviewed revision
+// @Description Publish a revision in review. The previously published revision is archived and content caches are invalidated
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Revision ID"
+// @Success 200 {object} RevisionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions/{id}/publish [post]
+func (h *AdminContentHandler) PublishRevision(c *gin.Context) {
+	h.revisionAction(c, http.StatusOK, func(id string) (*models.ContentRevision, error) {
+		return h.cms.Publish(c.Request.Context(), id, c.GetString("userID"))
+	})
+}
+
+// RollbackRevision godoc
+// @Summary Roll back to revision
+// @Description Publish the content of a previously published (published or archived) revision as a new revision
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Revision ID to roll back to"
+// @Success 201 {object} RevisionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/admin/content/revisions/{id}/rollback [post]
+func (h *AdminContentHandler) RollbackRevision(c *gin.Context) {
+	h.revisionAction(c, http.StatusCreated, func(id string) (*models.ContentRevision, error) {
+		return h.cms.Rollback(c.Request.Context(), id, c.GetString("userID"))
+	})
+}
+
+// revisionAction выполняет действие над ревизией из параметра id и отдает результат
+func (h *AdminContentHandler) revisionAction(c *gin.Context, status int, action func(id string) (*models.ContentRevision, error)) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Revision not found"})
+		return
+	}
+
+	rev, err := action(id)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(status, RevisionResponse{
+		Success: true,
+		Data:    rev,
+	})
+}
+
+// respondRevisionError переводит ошибки CMS в HTTP-ответ
+func respondRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, content.ErrInvalidContent):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, database.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Revision not found"})
+	case errors.Is(err, database.ErrInvalidRevisionStatus):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Revision status does not allow this action"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process revision"})
+	}
+}
+
+// Response types
+type RevisionResponse struct {
+	Success bool                    `json:"success"`
+	Data    *models.ContentRevision `json:"data"`
+}
+
+type RevisionListResponse struct {
+	Success bool                      `json:"success"`
+	Data    []*models.ContentRevision `json:"data"`
+}
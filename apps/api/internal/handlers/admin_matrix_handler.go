@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"arcanum/internal/services/calculator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminMatrixHandler struct{}
+
+func NewAdminMatrixHandler() *AdminMatrixHandler {
+	return &AdminMatrixHandler{}
+}
+
+type MatrixSearchRequest struct {
+	Main      int    `form:"main"`
+	Social    int    `form:"social"`
+	Spiritual int    `form:"spiritual"`
+	Tail      int    `form:"tail"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+type MatrixSearchResponse struct {
+	Success bool                          `json:"success"`
+	Data    calculator.MatrixSearchResult `json:"data"`
+}
+
+// SearchMatrix godoc
+// @Summary Reverse matrix search
+// @Description Find birth dates (from the minimum birth year to today) whose destiny matrix has the given arcana. Omitted points match any arcana. Returns matching dates and distribution statistics
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param main query int false "Main arcana (1-22)"
+// @Param social query int false "Social arcana (1-22)"
+// @Param spiritual query int false "Spiritual arcana (1-22)"
+// @Param tail query int false "Karmic tail arcana (1-22)"
+// @Param from query string false "Start of the date range"
+// @Param to query string false "End of the date range"
+// @Param limit query int false "Number of dates to return (default 100, max 1000)"
+// @Param offset query int false "Number of matching dates to skip"
+// @Success 200 {object} MatrixSearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/matrix/search [get]
+func (h *AdminMatrixHandler) SearchMatrix(c *gin.Context) {
+	var req MatrixSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query parameters"})
+		return
+	}
+
+	query := calculator.MatrixSearchQuery{
+		Main:      req.Main,
+		Social:    req.Social,
+		Spiritual: req.Spiritual,
+		Tail:      req.Tail,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	var err error
+	if query.From, err = parseOptionalDate(req.From); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if query.To, err = parseOptionalDate(req.To); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := calculator.SearchMatrix(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := MatrixSearchResponse{
+		Success: true,
+		Data:    *result,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
+
+// parseOptionalDate разбирает необязательную дату запроса (пустая строка - нулевая дата)
+func parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return calculator.ParseDate(value)
+}
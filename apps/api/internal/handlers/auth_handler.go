@@ -6,7 +6,9 @@ import (
 
 	"arcanum/internal/config"
 	"arcanum/internal/database"
+	"arcanum/internal/i18n"
 	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
 	"arcanum/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -63,12 +65,21 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// Язык по умолчанию берем из Accept-Language при регистрации
+	language, ok := i18n.MatchAcceptLanguage(c.GetHeader("Accept-Language"))
+	if !ok {
+		language = i18n.DefaultLanguage
+	}
+
 	// Создаем пользователя
 	user := &models.User{
 		ID:           uuid.New().String(),
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
 		Name:         req.Name,
+		Role:         models.UserRoleUser,
+		Language:     language,
+		Timezone:     calculator.DefaultTimezone,
 		IsPremium:    false,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
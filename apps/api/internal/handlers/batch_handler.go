@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"arcanum/internal/config"
+	"arcanum/internal/models"
+	"arcanum/internal/services/batch"
+	"arcanum/internal/services/calculator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// premiumCalculationTypes - расчеты, которые и в пакете доступны только Premium
+var premiumCalculationTypes = map[models.CalculationType]bool{
+	models.CalculationTypeCompatibility:      true,
+	models.CalculationTypeChildRole:          true,
+	models.CalculationTypeGroupCompatibility: true,
+	models.CalculationTypeFavorableDates:     true,
+}
+
+// premiumRows - проверки строк бесплатных типов, которым нужен Premium.
+// Строка без Premium получает ошибку, остальные строки рассчитываются
+var premiumRows = map[models.CalculationType]func(input json.RawMessage) error{
+	models.CalculationTypeForecasts: requireSingleYearForecast,
+}
+
+// requireSingleYearForecast отклоняет прогноз на несколько лет, как /calculate/forecasts
+func requireSingleYearForecast(input json.RawMessage) error {
+	if calculator.MultiYearForecast(input) {
+		return errors.New("multi-year forecasts require Premium subscription")
+	}
+	return nil
+}
+
+type BatchHandler struct {
+	registry *calculator.Registry
+	config   config.BatchConfig
+}
+
+func NewBatchHandler(registry *calculator.Registry, cfg config.BatchConfig) *BatchHandler {
+	return &BatchHandler{
+		registry: registry,
+		config:   cfg,
+	}
+}
+
+// CalculateBatch godoc
+// @Summary Batch calculation
+// @Description Calculate many inputs of one calculation type. Accepts a JSON array, NDJSON stream, CSV body or multipart upload (field "file"); results are streamed back in the same format with per-row errors. CSV columns are input fields, nested fields use dots (person1.birthDate, people.0.birthDate). Premium users get a larger row limit, premium calculation types and multi-year forecasts. Rows of type day require a date
+// @Tags calculations
+// @Accept json
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Accept multipart/form-data
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param type query string true "Calculation type (matrix, pythagoras, compatibility, child_role, age_timeline, name_number, group_compatibility, health_map, forecasts, day, favorable_dates)"
+// @Param version query int false "Algorithm version (default - latest)"
+// @Success 200 {array} object
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /api/v1/calculate/batch [post]
+func (h *BatchHandler) CalculateBatch(c *gin.Context) {
+	calcType := models.CalculationType(c.Query("type"))
+	if calcType == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Calculation type is required"})
+		return
+	}
+
+	// Версия 0 означает последнюю
+	version := 0
+	if versionParam := c.Query("version"); versionParam != "" {
+		v, err := strconv.Atoi(versionParam)
+		if err != nil || v < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid version"})
+			return
+		}
+		version = v
+	}
+
+	calc, err := h.registry.Get(calcType, version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Лимит строк зависит от подписки
+	isPremium, _ := c.Get("isPremium")
+	premium, _ := isPremium.(bool)
+	if premiumCalculationTypes[calcType] && !premium {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "This calculation type requires Premium subscription"})
+		return
+	}
+	maxRows := h.config.MaxRowsFree
+	if premium {
+		maxRows = h.config.MaxRowsPremium
+	}
+
+	// Тело читается потоково и не больше MaxBodyBytes
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.MaxBodyBytes)
+	format, input, err := batchInput(c)
+	if err != nil {
+		respondBatchInputError(c, err)
+		return
+	}
+
+	reader, err := batch.NewReader(format, limitedBody{r: input, limit: h.config.MaxBodyBytes})
+	if err != nil {
+		respondBatchInputError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("X-Batch-Max-Rows", strconv.Itoa(maxRows))
+	c.Header("X-Algorithm-Version", strconv.Itoa(calc.Version()))
+	c.Status(http.StatusOK)
+
+	writer, err := batch.NewWriter(format, c.Writer, reader.Columns())
+	if err != nil {
+		return
+	}
+
+	lang, bundle := requestLanguage(c), messageBundle(c)
+	checkRow := premiumRows[calcType]
+	calculate := func(input json.RawMessage) (interface{}, error) {
+		if checkRow != nil && !premium {
+			if err := checkRow(input); err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := calc.Calculate(input)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Localize(result, lang)
+		return result, nil
+	}
+
+	// Ошибка записи означает, что клиент закрыл соединение
+	_, _ = batch.Run(reader, writer, maxRows, calculate, c.Writer.Flush)
+}
+
+// batchInput возвращает формат и поток входных данных: тело запроса или
+// файл из поля file при загрузке multipart/form-data
+func batchInput(c *gin.Context) (batch.Format, io.Reader, error) {
+	contentType := c.GetHeader("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "multipart/form-data" {
+		format, err := batch.FormatFromContentType(contentType)
+		return format, c.Request.Body, err
+	}
+
+	parts, err := c.Request.MultipartReader()
+	if err != nil {
+		return "", nil, err
+	}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			return "", nil, errors.New("multipart upload must contain a file field")
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		format, err := batch.FormatFromFilename(part.FileName())
+		if err != nil && part.Header.Get("Content-Type") != "" {
+			format, err = batch.FormatFromContentType(part.Header.Get("Content-Type"))
+		}
+		return format, part, err
+	}
+}
+
+// limitedBody заменяет ошибку превышения размера тела понятным сообщением,
+// которое попадает в строку с ошибкой, если лимит достигнут во время расчета
+type limitedBody struct {
+	r     io.Reader
+	limit int64
+}
+
+func (b limitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = fmt.Errorf("request body exceeds the limit of %d bytes: %w", b.limit, err)
+	}
+	return n, err
+}
+
+// respondBatchInputError отвечает на ошибку до начала потоковой записи результатов
+func respondBatchInputError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body is too large"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+}
@@ -1,16 +1,27 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
+	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
+
 	"github.com/gin-gonic/gin"
-	"github.com/akhludeyev/arcanum/api/internal/services/calculator"
 )
 
-type CalculationHandler struct{}
+// CalculationHandler считает теми же функциями, что и последние версии алгоритмов реестра.
+// Версия возвращается в ответе как algorithmVersion. Тексты психоматрицы и совместимости
+// берутся из опубликованного в CMS контента
+type CalculationHandler struct {
+	registry *calculator.Registry
+}
 
-func NewCalculationHandler() *CalculationHandler {
-	return &CalculationHandler{}
+func NewCalculationHandler(registry *calculator.Registry) *CalculationHandler {
+	return &CalculationHandler{
+		registry: registry,
+	}
 }
 
 // CalculateMatrix godoc
@@ -20,39 +31,33 @@ func NewCalculationHandler() *CalculationHandler {
 // @Accept json
 // @Produce json
 // @Param request body MatrixRequest true "Birth date"
+// @Param explain query bool false "Return a step-by-step calculation trace"
 // @Success 200 {object} MatrixResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /api/v1/calculate/matrix [post]
 func (h *CalculationHandler) CalculateMatrix(c *gin.Context) {
 	var req MatrixRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
 		return
 	}
 
-	result, err := calculator.CalculateMatrixFate(req.BirthDate)
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateMatrixReportWithTrace(req.BirthDate, trace)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Добавляем названия арканов
 	response := MatrixResponse{
-		Success: true,
-		Data: MatrixData{
-			Main:      result.Main,
-			Social:    result.Social,
-			Spiritual: result.Spiritual,
-			Tail:      result.Tail,
-			ArcanaNames: ArcanaNames{
-				Main:      calculator.GetArcanaName(result.Main),
-				Social:    calculator.GetArcanaName(result.Social),
-				Spiritual: calculator.GetArcanaName(result.Spiritual),
-				Tail:      calculator.GetArcanaName(result.Tail),
-			},
-		},
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeMatrix),
+		Data:             *result,
+		Trace:            trace,
 	}
 
+	localize(c, &response)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -62,45 +67,46 @@ func (h *CalculationHandler) CalculateMatrix(c *gin.Context) {
 // @Tags calculations
 // @Accept json
 // @Produce json
-// @Param request body MatrixRequest true "Birth date"
+// @Param request body PythagorasRequest true "Birth date and calculation mode"
+// @Param explain query bool false "Return a step-by-step calculation trace"
 // @Success 200 {object} PythagorasResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /api/v1/calculate/pythagoras [post]
 func (h *CalculationHandler) CalculatePythagoras(c *gin.Context) {
-	var req MatrixRequest
+	var req PythagorasRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
 		return
 	}
 
-	result, err := calculator.CalculatePythagoras(req.BirthDate)
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculatePythagorasReportWithTrace(req.BirthDate, req.Mode, trace)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	interpretations := calculator.InterpretPythagoras(result)
-
 	response := PythagorasResponse{
-		Success: true,
-		Data: PythagorasData{
-			Cells:           result.Cells,
-			Lines:           result.Lines,
-			Interpretations: interpretations,
-		},
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypePythagoras),
+		Data:             *result,
+		Trace:            trace,
 	}
 
+	localize(c, &response)
 	c.JSON(http.StatusOK, response)
 }
 
 // CalculateCompatibility godoc
 // @Summary Calculate Compatibility
-// @Description Calculate compatibility between two people (Premium feature)
+// @Description Calculate compatibility between two people with a scoring profile: romantic (default), business or friendship (Premium feature)
 // @Tags calculations
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body CompatibilityRequest true "Two people's birth dates"
+// @Param request body CompatibilityRequest true "Two people's birth dates and scoring profile"
+// @Param explain query bool false "Return a step-by-step calculation trace"
 // @Success 200 {object} CompatibilityResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -108,87 +114,551 @@ func (h *CalculationHandler) CalculatePythagoras(c *gin.Context) {
 func (h *CalculationHandler) CalculateCompatibility(c *gin.Context) {
 	var req CompatibilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
 		return
 	}
 
-	// Рассчитываем матрицы для обоих людей
-	person1, err := calculator.CalculateMatrixFate(req.Person1.BirthDate)
+	trace := explainTrace(c)
+
+	// Совместимость по выбранному профилю и матрица пары
+	result, err := calculator.CalculateCompatibilityReportWithTrace(req.Person1.BirthDate, req.Person2.BirthDate, req.Profile, trace)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid birth date for person 1"})
+		var dateErr *calculator.PersonDateError
+		if errors.As(err, &dateErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid birth date for person %d", dateErr.Person)})
+			return
+		}
+		if errors.Is(err, calculator.ErrUnknownCompatibilityProfile) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	person2, err := calculator.CalculateMatrixFate(req.Person2.BirthDate)
+	response := CompatibilityResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeCompatibility),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
+
+// CalculateGroupCompatibility godoc
+// @Summary Calculate Group Compatibility
+// @Description Calculate pairwise compatibility for a group of 3-10 people (Premium feature)
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GroupCompatibilityRequest true "Birth dates of group members"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} GroupCompatibilityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/calculate/compatibility/group [post]
+func (h *CalculationHandler) CalculateGroupCompatibility(c *gin.Context) {
+	var req GroupCompatibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	// Рассчитываем матрицы всех участников
+	members := make([]calculator.GroupMember, 0, len(req.People))
+	for i, person := range req.People {
+		matrix, err := calculator.CalculateMatrixFateWithTrace(person.BirthDate, trace.Scope(fmt.Sprintf("members[%d]", i)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid birth date for person %d", i+1)})
+			return
+		}
+		members = append(members, calculator.GroupMember{Name: person.Name, Matrix: matrix})
+	}
+
+	result, err := calculator.CalculateGroupCompatibilityWithTrace(members, trace)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid birth date for person 2"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := GroupCompatibilityResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeGroupCompatibility),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
+
+// CalculateChildRole godoc
+// @Summary Calculate Child Role
+// @Description Calculate child's role in the family and karmic tasks with parents (Premium feature)
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChildRoleRequest true "Child's and parents' birth dates"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} ChildRoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/calculate/child-role [post]
+func (h *CalculationHandler) CalculateChildRole(c *gin.Context) {
+	var req ChildRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
 		return
 	}
 
-	// Рассчитываем совместимость
-	result, err := calculator.CalculateCompatibility(person1, person2)
+	var siblingDate *calculator.BirthDate
+	if req.ExistingChild != nil {
+		siblingDate = &req.ExistingChild.BirthDate
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateChildRoleWithTrace(req.ChildDate, req.Parent1.BirthDate, req.Parent2.BirthDate, siblingDate, trace)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	response := CompatibilityResponse{
-		Success: true,
-		Data:    *result,
+	response := ChildRoleResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeChildRole),
+		Data:             *result,
+		Trace:            trace,
 	}
 
+	localize(c, &response)
 	c.JSON(http.StatusOK, response)
 }
 
-// Request/Response types
-type MatrixRequest struct {
-	BirthDate string `json:"birthDate" binding:"required"`
+// CalculateFavorableDates godoc
+// @Summary Find Favorable Dates
+// @Description Score every day of a range (up to a year) for a wedding, launch or contract and return the best dates with explanations. Dates with challenging arcana such as 16 (Tower) are excluded (Premium feature)
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FavorableDatesRequest true "Event type, date range and one or two participants"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} FavorableDatesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/calculate/favorable-dates [post]
+func (h *CalculationHandler) CalculateFavorableDates(c *gin.Context) {
+	var req FavorableDatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	from, err := calculator.ParseDate(req.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	to, err := calculator.ParseDate(req.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	// Рассчитываем матрицы участников
+	participants := make([]calculator.GroupMember, 0, len(req.Participants))
+	for i, person := range req.Participants {
+		matrix, err := calculator.CalculateMatrixFateWithTrace(person.BirthDate, trace.Scope(fmt.Sprintf("participants[%d]", i)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid birth date for participant %d", i+1)})
+			return
+		}
+		participants = append(participants, calculator.GroupMember{Name: person.Name, Matrix: matrix})
+	}
+
+	result, err := calculator.CalculateFavorableDatesWithTrace(req.Event, participants, from, to, req.Limit, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := FavorableDatesResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeFavorableDates),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
 }
 
-type MatrixResponse struct {
-	Success bool       `json:"success"`
-	Data    MatrixData `json:"data"`
+// CalculateForecasts godoc
+// @Summary Calculate Forecasts
+// @Description Calculate personal year, month and age point forecasts. Multi-year ranges are a Premium feature
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Param request body ForecastRequest true "Birth date and target year"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} ForecastResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/calculate/forecasts [post]
+func (h *CalculationHandler) CalculateForecasts(c *gin.Context) {
+	var req ForecastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	endYear := req.Year
+	if req.EndYear != 0 {
+		endYear = req.EndYear
+	}
+
+	// Прогноз на несколько лет доступен только Premium пользователям
+	if endYear != req.Year {
+		isPremium, _ := c.Get("isPremium")
+		if premium, ok := isPremium.(bool); !ok || !premium {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Multi-year forecasts require Premium subscription"})
+			return
+		}
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateForecastsWithTrace(req.BirthDate, req.Year, endYear, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := ForecastResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeForecasts),
+		Data:             result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
+
+// CalculateAgeTimeline godoc
+// @Summary Calculate Age Timeline
+// @Description Calculate age line segments along the matrix perimeter (0-80 years) and optionally the active segment for an age or date
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Param request body AgeTimelineRequest true "Birth date and optional age or date"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} AgeTimelineResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/calculate/age-timeline [post]
+func (h *CalculationHandler) CalculateAgeTimeline(c *gin.Context) {
+	var req AgeTimelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	// Линия возраста и активная программа на возраст или на дату
+	result, err := calculator.CalculateAgeTimelineReportWithTrace(req.BirthDate, req.Age, req.Date, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := AgeTimelineResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeAgeTimeline),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
 }
 
-type MatrixData struct {
-	Main        int         `json:"main"`
-	Social      int         `json:"social"`
-	Spiritual   int         `json:"spiritual"`
-	Tail        int         `json:"tail"`
-	ArcanaNames ArcanaNames `json:"arcanaNames"`
+// CalculateHealthMap godoc
+// @Summary Calculate Health Map
+// @Description Calculate chakra health map based on birth date
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Param request body MatrixRequest true "Birth date"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} HealthMapResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/calculate/health-map [post]
+func (h *CalculationHandler) CalculateHealthMap(c *gin.Context) {
+	var req MatrixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateHealthMapWithTrace(req.BirthDate, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := HealthMapResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeHealthMap),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
 }
 
-type ArcanaNames struct {
-	Main      string `json:"main"`
-	Social    string `json:"social"`
-	Spiritual string `json:"spiritual"`
-	Tail      string `json:"tail"`
+// CalculateNameNumber godoc
+// @Summary Calculate Name Number
+// @Description Calculate expression, soul urge and personality numbers from a full name (Cyrillic or Latin)
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Param request body NameNumberRequest true "Full name"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} NameNumberResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/calculate/name-number [post]
+func (h *CalculationHandler) CalculateNameNumber(c *gin.Context) {
+	var req NameNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateNameNumberWithTrace(req.FirstName, req.Patronymic, req.LastName, req.Alphabet, req.Reduction, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := NameNumberResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeNameNumber),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
 }
 
-type PythagorasResponse struct {
-	Success bool            `json:"success"`
-	Data    PythagorasData  `json:"data"`
+// CalculateDay godoc
+// @Summary Calculate Energy of the Day
+// @Description Calculate the personal arcana of a day and the next 7 days. Without a date the current day is taken in the given timezone (UTC by default)
+// @Tags calculations
+// @Accept json
+// @Produce json
+// @Param request body DayEnergyRequest true "Birth date, optional date and timezone"
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} DayEnergyResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/calculate/day [post]
+func (h *CalculationHandler) CalculateDay(c *gin.Context) {
+	var req DayEnergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: bindErrorMessage(err)})
+		return
+	}
+
+	today, err := calculator.Today(req.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	date := today
+	if req.Date != "" {
+		date, err = calculator.ParseDate(req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateDayEnergyReportWithTrace(req.BirthDate, date, req.Timezone, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := DayEnergyResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeDay),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
+
+// Request/Response types
+type MatrixRequest struct {
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+}
+
+type MatrixResponse struct {
+	Success          bool                    `json:"success"`
+	AlgorithmVersion int                     `json:"algorithmVersion"`
+	Data             calculator.MatrixReport `json:"data"`
+	Trace            *calculator.Trace       `json:"trace,omitempty"`
 }
 
-type PythagorasData struct {
-	Cells           map[int]int       `json:"cells"`
-	Lines           calculator.Lines  `json:"lines"`
-	Interpretations map[string]string `json:"interpretations"`
+type PythagorasRequest struct {
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+	Mode      string               `json:"mode"`
+}
+
+type PythagorasResponse struct {
+	Success          bool                        `json:"success"`
+	AlgorithmVersion int                         `json:"algorithmVersion"`
+	Data             calculator.PythagorasReport `json:"data"`
+	Trace            *calculator.Trace           `json:"trace,omitempty"`
 }
 
 type CompatibilityRequest struct {
 	Person1 PersonData `json:"person1" binding:"required"`
 	Person2 PersonData `json:"person2" binding:"required"`
+	Profile string     `json:"profile"`
 }
 
 type PersonData struct {
-	BirthDate string `json:"birthDate" binding:"required"`
-	Name      string `json:"name"`
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+	Name      string               `json:"name"`
 }
 
 type CompatibilityResponse struct {
-	Success bool                            `json:"success"`
-	Data    calculator.CompatibilityResult  `json:"data"`
+	Success          bool                           `json:"success"`
+	AlgorithmVersion int                            `json:"algorithmVersion"`
+	Data             calculator.CompatibilityReport `json:"data"`
+	Trace            *calculator.Trace              `json:"trace,omitempty"`
+}
+
+type GroupCompatibilityRequest struct {
+	People []PersonData `json:"people" binding:"required,min=3,max=10,dive"`
+}
+
+type GroupCompatibilityResponse struct {
+	Success          bool                                `json:"success"`
+	AlgorithmVersion int                                 `json:"algorithmVersion"`
+	Data             calculator.GroupCompatibilityResult `json:"data"`
+	Trace            *calculator.Trace                   `json:"trace,omitempty"`
+}
+
+type ChildRoleRequest struct {
+	ChildDate     calculator.BirthDate `json:"childDate" binding:"required,birthdate"`
+	Parent1       PersonData           `json:"parent1" binding:"required"`
+	Parent2       PersonData           `json:"parent2" binding:"required"`
+	ExistingChild *PersonData          `json:"existingChild"`
+}
+
+type ChildRoleResponse struct {
+	Success          bool                       `json:"success"`
+	AlgorithmVersion int                        `json:"algorithmVersion"`
+	Data             calculator.ChildRoleResult `json:"data"`
+	Trace            *calculator.Trace          `json:"trace,omitempty"`
+}
+
+type FavorableDatesRequest struct {
+	Event        string       `json:"event" binding:"required"`
+	From         string       `json:"from" binding:"required"`
+	To           string       `json:"to" binding:"required"`
+	Participants []PersonData `json:"participants" binding:"required,min=1,max=2,dive"`
+	Limit        int          `json:"limit"`
+}
+
+type FavorableDatesResponse struct {
+	Success          bool                            `json:"success"`
+	AlgorithmVersion int                             `json:"algorithmVersion"`
+	Data             calculator.FavorableDatesResult `json:"data"`
+	Trace            *calculator.Trace               `json:"trace,omitempty"`
+}
+
+type ForecastRequest struct {
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+	Year      int                  `json:"year" binding:"required"`
+	EndYear   int                  `json:"endYear"`
+}
+
+type ForecastResponse struct {
+	Success          bool                  `json:"success"`
+	AlgorithmVersion int                   `json:"algorithmVersion"`
+	Data             []calculator.Forecast `json:"data"`
+	Trace            *calculator.Trace     `json:"trace,omitempty"`
+}
+
+type AgeTimelineRequest struct {
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+	Age       *float64             `json:"age"`
+	Date      string               `json:"date"`
+}
+
+type AgeTimelineResponse struct {
+	Success          bool                         `json:"success"`
+	AlgorithmVersion int                          `json:"algorithmVersion"`
+	Data             calculator.AgeTimelineReport `json:"data"`
+	Trace            *calculator.Trace            `json:"trace,omitempty"`
+}
+
+type HealthMapResponse struct {
+	Success          bool                 `json:"success"`
+	AlgorithmVersion int                  `json:"algorithmVersion"`
+	Data             calculator.HealthMap `json:"data"`
+	Trace            *calculator.Trace    `json:"trace,omitempty"`
+}
+
+type NameNumberRequest struct {
+	FirstName  string `json:"firstName" binding:"required"`
+	Patronymic string `json:"patronymic"`
+	LastName   string `json:"lastName"`
+	Alphabet   string `json:"alphabet"`
+	Reduction  string `json:"reduction"`
+}
+
+type NameNumberResponse struct {
+	Success          bool                  `json:"success"`
+	AlgorithmVersion int                   `json:"algorithmVersion"`
+	Data             calculator.NameNumber `json:"data"`
+	Trace            *calculator.Trace     `json:"trace,omitempty"`
+}
+
+type DayEnergyRequest struct {
+	BirthDate calculator.BirthDate `json:"birthDate" binding:"required,birthdate"`
+	Date      string               `json:"date"`
+	Timezone  string               `json:"timezone"`
+}
+
+type DayEnergyResponse struct {
+	Success          bool                       `json:"success"`
+	AlgorithmVersion int                        `json:"algorithmVersion"`
+	Data             calculator.DayEnergyReport `json:"data"`
+	Trace            *calculator.Trace          `json:"trace,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"arcanum/internal/database"
+	"arcanum/internal/i18n"
 	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,18 +17,28 @@ import (
 
 type CalculationStorageHandler struct {
 	calcRepo *database.CalculationRepository
+	registry *calculator.Registry
 }
 
-func NewCalculationStorageHandler(calcRepo *database.CalculationRepository) *CalculationStorageHandler {
+func NewCalculationStorageHandler(calcRepo *database.CalculationRepository, registry *calculator.Registry) *CalculationStorageHandler {
 	return &CalculationStorageHandler{
 		calcRepo: calcRepo,
+		registry: registry,
 	}
 }
 
 type SaveCalculationRequest struct {
-	Type       models.CalculationType `json:"type" binding:"required"`
-	InputData  interface{}            `json:"inputData" binding:"required"`
-	ResultData interface{}            `json:"resultData" binding:"required"`
+	Type             models.CalculationType `json:"type" binding:"required"`
+	AlgorithmVersion int                    `json:"algorithmVersion"`
+	Language         string                 `json:"language"`
+	InputData        interface{}            `json:"inputData" binding:"required"`
+	ResultData       interface{}            `json:"resultData" binding:"required"`
+}
+
+type RecomputeResponse struct {
+	Calculation      *models.Calculation `json:"calculation"`
+	AlgorithmVersion int                 `json:"algorithmVersion"`
+	ResultData       interface{}         `json:"resultData"`
 }
 
 // SaveCalculation сохраняет расчет пользователя
@@ -42,14 +56,40 @@ func (h *CalculationStorageHandler) SaveCalculation(c *gin.Context) {
 		return
 	}
 
+	// Версия алгоритма: algorithmVersion из ответа /calculate/* или последняя известная.
+	// Без версии расчет, сделанный до выхода новой версии, получит ее номер
+	version := req.AlgorithmVersion
+	if version == 0 {
+		version = h.registry.LatestVersion(req.Type)
+	} else if _, err := h.registry.Get(req.Type, version); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown algorithm version"})
+		return
+	}
+	if version == 0 {
+		// Тип без зарегистрированного расчета сохраняется с первой версией
+		version = 1
+	}
+
+	// Язык текстов resultData: language из запроса или язык ответа /calculate/*
+	// (Accept-Language, затем настройки пользователя)
+	language := req.Language
+	if language == "" {
+		language = requestLanguage(c)
+	} else if !i18n.IsSupported(language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+
 	// Создаем расчет
 	calc := &models.Calculation{
-		ID:         uuid.New().String(),
-		UserID:     userID.(string),
-		Type:       req.Type,
-		InputData:  req.InputData,
-		ResultData: req.ResultData,
-		CreatedAt:  time.Now(),
+		ID:               uuid.New().String(),
+		UserID:           userID.(string),
+		Type:             req.Type,
+		AlgorithmVersion: version,
+		Language:         language,
+		InputData:        req.InputData,
+		ResultData:       req.ResultData,
+		CreatedAt:        time.Now(),
 	}
 
 	if err := h.calcRepo.Create(c.Request.Context(), calc); err != nil {
@@ -149,3 +189,74 @@ func (h *CalculationStorageHandler) DeleteCalculation(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Calculation deleted successfully"})
 }
+
+// RecomputeCalculation пересчитывает сохраненные входные данные выбранной версией алгоритма.
+// Сохраненный результат не изменяется
+func (h *CalculationStorageHandler) RecomputeCalculation(c *gin.Context) {
+	// Получаем ID пользователя из контекста
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	calcID := c.Param("id")
+	if calcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Calculation ID is required"})
+		return
+	}
+
+	// Версия 0 означает последнюю
+	version := 0
+	if versionParam := c.Query("version"); versionParam != "" {
+		v, err := strconv.Atoi(versionParam)
+		if err != nil || v < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+			return
+		}
+		version = v
+	}
+
+	calc, err := h.calcRepo.FindByID(c.Request.Context(), calcID)
+	if err != nil {
+		if err == database.ErrCalculationNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Calculation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get calculation"})
+		return
+	}
+
+	// Проверяем, что расчет принадлежит пользователю
+	if calc.UserID != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	calculatorImpl, err := h.registry.Get(calc.Type, version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input, err := json.Marshal(calc.InputData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read input data"})
+		return
+	}
+
+	result, err := calculatorImpl.Calculate(input)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := RecomputeResponse{
+		Calculation:      calc,
+		AlgorithmVersion: calculatorImpl.Version(),
+		ResultData:       result,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
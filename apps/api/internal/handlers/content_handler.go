@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+	"arcanum/internal/models"
+	"arcanum/internal/services/arcana"
+	"arcanum/internal/services/content"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentCacheControl - клиенты перепроверяют контент по ETag при каждом запросе,
+// чтобы опубликованные правки были видны сразу
+const contentCacheControl = "public, no-cache"
+
+type ContentHandler struct {
+	contentRepo *database.ContentRepository
+	cache       *content.Cache
+	texts       *content.Texts
+}
+
+func NewContentHandler(contentRepo *database.ContentRepository, cache *content.Cache, texts *content.Texts) *ContentHandler {
+	return &ContentHandler{
+		contentRepo: contentRepo,
+		cache:       cache,
+		texts:       texts,
+	}
+}
+
+// GetArcana godoc
+// @Summary Get arcana interpretation
+// @Description Get interpretation texts of a single arcana. Falls back to Russian if the language is not available
+// @Tags content
+// @Produce json
+// @Param id path int true "Arcana number (1-22)"
+// @Param lang query string false "Language (default ru)"
+// @Success 200 {object} ArcanaContentResponse
+// @Success 304
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/content/arcana/{id} [get]
+func (h *ContentHandler) GetArcana(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("id"))
+	if err != nil || number < 1 || number > arcana.Count {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Arcana number must be between 1 and 22"})
+		return
+	}
+
+	language := contentLanguage(c)
+	cacheKey := fmt.Sprintf("arcana:%d:%s", number, language)
+	if h.serveCached(c, cacheKey) {
+		return
+	}
+
+	item, err := h.contentRepo.FindArcana(c.Request.Context(), number, language)
+	if errors.Is(err, database.ErrInterpretationNotFound) && language != arcana.DefaultLanguage {
+		item, err = h.contentRepo.FindArcana(c.Request.Context(), number, arcana.DefaultLanguage)
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrInterpretationNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Interpretation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get interpretation"})
+		return
+	}
+
+	h.respondCached(c, cacheKey, item.Language, ArcanaContentResponse{
+		Success: true,
+		Data:    item,
+	})
+}
+
+// GetInterpretations godoc
+// @Summary Get all arcana interpretations
+// @Description Get interpretation texts of all 22 arcanas. Falls back to Russian if the language is not available
+// @Tags content
+// @Produce json
+// @Param lang query string false "Language (default ru)"
+// @Success 200 {object} InterpretationsResponse
+// @Success 304
+// @Router /api/v1/content/interpretations [get]
+func (h *ContentHandler) GetInterpretations(c *gin.Context) {
+	language := contentLanguage(c)
+	cacheKey := "interpretations:" + language
+	if h.serveCached(c, cacheKey) {
+		return
+	}
+
+	items, err := h.contentRepo.ListArcana(c.Request.Context(), language)
+	if err == nil && len(items) == 0 && language != arcana.DefaultLanguage {
+		language = arcana.DefaultLanguage
+		items, err = h.contentRepo.ListArcana(c.Request.Context(), language)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get interpretations"})
+		return
+	}
+
+	h.respondCached(c, cacheKey, language, InterpretationsResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// GetPythagoras godoc
+// @Summary Get Pythagoras square interpretations
+// @Description Get interpretation texts of psychomatrix cells, rows, columns and diagonals. Falls back to Russian if the language is not available
+// @Tags content
+// @Produce json
+// @Param lang query string false "Language (default ru)"
+// @Success 200 {object} PythagorasContentResponse
+// @Success 304
+// @Router /api/v1/content/pythagoras [get]
+func (h *ContentHandler) GetPythagoras(c *gin.Context) {
+	language := contentLanguage(c)
+	cacheKey := "pythagoras:" + language
+	if h.serveCached(c, cacheKey) {
+		return
+	}
+
+	items, err := h.contentRepo.ListPythagoras(c.Request.Context(), language)
+	if err == nil && len(items) == 0 && language != arcana.DefaultLanguage {
+		language = arcana.DefaultLanguage
+		items, err = h.contentRepo.ListPythagoras(c.Request.Context(), language)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get interpretations"})
+		return
+	}
+
+	h.respondCached(c, cacheKey, language, PythagorasContentResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// GetCompatibilityTexts godoc
+// @Summary Get compatibility texts
+// @Description Get the texts used in compatibility results: built-in texts with edits published in the CMS applied. Keys are message keys without the compatibility. prefix. Falls back to Russian if the language is not available
+// @Tags content
+// @Produce json
+// @Param lang query string false "Language (default ru)"
+// @Success 200 {object} CompatibilityTextsResponse
+// @Success 304
+// @Router /api/v1/content/compatibility [get]
+func (h *ContentHandler) GetCompatibilityTexts(c *gin.Context) {
+	language := contentLanguage(c)
+	if !i18n.IsSupported(language) {
+		language = i18n.DefaultLanguage
+	}
+	cacheKey := "compatibility:" + language
+	if h.serveCached(c, cacheKey) {
+		return
+	}
+
+	bundle := h.texts.Bundle(c.Request.Context())
+	keys := bundle.Keys(content.CompatibilityTextPrefix)
+	items := make([]*models.CompatibilityText, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, &models.CompatibilityText{
+			Key:      strings.TrimPrefix(key, content.CompatibilityTextPrefix),
+			Language: language,
+			Text:     bundle.Message(language, key),
+		})
+	}
+
+	h.respondCached(c, cacheKey, language, CompatibilityTextsResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// contentLanguage возвращает язык контента из параметра lang
+func contentLanguage(c *gin.Context) string {
+	language := strings.ToLower(strings.TrimSpace(c.Query("lang")))
+	if language == "" {
+		return arcana.DefaultLanguage
+	}
+	return language
+}
+
+// serveCached отдает ответ из кэша, если он есть
+func (h *ContentHandler) serveCached(c *gin.Context, key string) bool {
+	entry, ok := h.cache.Get(c.Request.Context(), key)
+	if !ok {
+		return false
+	}
+
+	writeContent(c, entry)
+	return true
+}
+
+// respondCached сериализует ответ, сохраняет его в кэш и отдает клиенту.
+// Ошибка записи в кэш не мешает ответу
+func (h *ContentHandler) respondCached(c *gin.Context, key, language string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to encode response"})
+		return
+	}
+
+	entry := &content.Entry{Language: language, Body: body}
+	_ = h.cache.Set(c.Request.Context(), key, entry)
+
+	writeContent(c, entry)
+}
+
+// writeContent отдает JSON с ETag по содержимому и Cache-Control.
+// Если клиент прислал совпадающий If-None-Match, возвращается 304 без тела
+func writeContent(c *gin.Context, entry *content.Entry) {
+	sum := sha256.Sum256(entry.Body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", contentCacheControl)
+	c.Header("Content-Language", entry.Language)
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", entry.Body)
+}
+
+// Response types
+type ArcanaContentResponse struct {
+	Success bool                         `json:"success"`
+	Data    *models.ArcanaInterpretation `json:"data"`
+}
+
+type InterpretationsResponse struct {
+	Success bool                           `json:"success"`
+	Data    []*models.ArcanaInterpretation `json:"data"`
+}
+
+type PythagorasContentResponse struct {
+	Success bool                                      `json:"success"`
+	Data    []*models.PythagorasElementInterpretation `json:"data"`
+}
+
+type CompatibilityTextsResponse struct {
+	Success bool                        `json:"success"`
+	Data    []*models.CompatibilityText `json:"data"`
+}
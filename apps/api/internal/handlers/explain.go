@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"strconv"
+
+	"arcanum/internal/services/calculator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// explainTrace возвращает трассировку расчета, если запрос передал
+// ?explain=true, иначе nil - расчет выполняется без записи шагов
+func explainTrace(c *gin.Context) *calculator.Trace {
+	explain, _ := strconv.ParseBool(c.Query("explain"))
+	if !explain {
+		return nil
+	}
+	return calculator.NewTrace()
+}
@@ -3,8 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"arcanum/internal/database"
+
 	"github.com/gin-gonic/gin"
-	"github.com/akhludeyev/arcanum/api/internal/database"
 )
 
 type HealthHandler struct {
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/content"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLanguage возвращает язык ответа, выбранный middleware.Language
+func requestLanguage(c *gin.Context) string {
+	if lang := c.GetString("language"); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// localize подставляет в ответ тексты на языке запроса.
+// payload должен быть указателем на ответ
+func localize(c *gin.Context, payload interface{}) {
+	messageBundle(c).Localize(payload, requestLanguage(c))
+}
+
+// messageBundle возвращает каталоги сообщений с опубликованными в CMS текстами
+// (middleware.ContentTexts) или встроенные каталоги
+func messageBundle(c *gin.Context) *i18n.Bundle {
+	if texts, ok := c.Get("contentTexts"); ok {
+		return texts.(*content.Texts).Bundle(c.Request.Context())
+	}
+	return i18n.Default()
+}
@@ -4,23 +4,30 @@ import (
 	"net/http"
 
 	"arcanum/internal/database"
+	"arcanum/internal/i18n"
 	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
 	userRepo *database.UserRepository
+	registry *calculator.Registry
 }
 
-func NewUserHandler(userRepo *database.UserRepository) *UserHandler {
+func NewUserHandler(userRepo *database.UserRepository, registry *calculator.Registry) *UserHandler {
 	return &UserHandler{
 		userRepo: userRepo,
+		registry: registry,
 	}
 }
 
 type UpdateProfileRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name      string               `json:"name" binding:"required"`
+	Language  string               `json:"language"`
+	BirthDate calculator.BirthDate `json:"birthDate"`
+	Timezone  string               `json:"timezone"`
 }
 
 // GetProfile возвращает профиль текущего пользователя
@@ -63,6 +70,18 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	if req.Language != "" && !i18n.IsSupported(req.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+
+	if req.Timezone != "" {
+		if _, err := calculator.LoadTimezone(req.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown timezone"})
+			return
+		}
+	}
+
 	// Получаем текущего пользователя
 	user, err := h.userRepo.FindByID(c.Request.Context(), userID.(string))
 	if err != nil {
@@ -72,6 +91,16 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	// Обновляем данные
 	user.Name = req.Name
+	if req.Language != "" {
+		user.Language = req.Language
+	}
+	if !req.BirthDate.IsZero() {
+		birthDate := req.BirthDate.String()
+		user.BirthDate = &birthDate
+	}
+	if req.Timezone != "" {
+		user.Timezone = req.Timezone
+	}
 
 	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
@@ -83,3 +112,67 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	c.JSON(http.StatusOK, user)
 }
+
+// GetToday godoc
+// @Summary Energy of the day
+// @Description Personal arcana of the current day and the next 7 days. The current date is taken in the user's timezone from the profile
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param explain query bool false "Return a step-by-step calculation trace"
+// @Success 200 {object} DayEnergyResponse
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/today [get]
+func (h *UserHandler) GetToday(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID.(string))
+	if err != nil {
+		if err == database.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if user.BirthDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Birth date is not set in profile"})
+		return
+	}
+
+	birthDate, err := calculator.ParseBirthDate(*user.BirthDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Текущая дата берется в часовом поясе пользователя, а не сервера
+	today, err := calculator.Today(user.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trace := explainTrace(c)
+
+	result, err := calculator.CalculateDayEnergyReportWithTrace(birthDate, today, user.Timezone, trace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := DayEnergyResponse{
+		Success:          true,
+		AlgorithmVersion: h.registry.LatestVersion(models.CalculationTypeDay),
+		Data:             *result,
+		Trace:            trace,
+	}
+
+	localize(c, &response)
+	c.JSON(http.StatusOK, response)
+}
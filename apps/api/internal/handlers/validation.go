@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"arcanum/internal/services/calculator"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidators регистрирует пользовательские теги валидации в Gin:
+// birthdate - строка или calculator.BirthDate с корректной датой рождения
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	// BirthDate валидируется как строка DD.MM.YYYY, чтобы работали теги required и birthdate
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if date, ok := field.Interface().(calculator.BirthDate); ok {
+			return date.String()
+		}
+		return nil
+	}, calculator.BirthDate{})
+
+	return v.RegisterValidation("birthdate", validateBirthDate)
+}
+
+// validateBirthDate проверяет, что значение поля - существующая дата рождения в допустимом диапазоне
+func validateBirthDate(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+	_, err := calculator.ParseBirthDate(fl.Field().String())
+	return err == nil
+}
+
+// bindErrorMessage формирует понятное сообщение об ошибке разбора запроса.
+// Ошибки в датах рождения возвращаются с причиной, остальные - общим сообщением
+func bindErrorMessage(err error) string {
+	var dateErr *calculator.DateError
+	if errors.As(err, &dateErr) {
+		return dateErr.Error()
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			if fieldErr.Tag() == "birthdate" {
+				return fmt.Sprintf("Invalid birth date in field %s", fieldErr.Field())
+			}
+		}
+	}
+
+	return "Invalid request body"
+}
@@ -0,0 +1,214 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/services/arcana"
+
+	"golang.org/x/text/language"
+)
+
+// Поддерживаемые языки
+const (
+	Russian = "ru"
+	English = "en"
+
+	// DefaultLanguage - язык, на который откатываются отсутствующие переводы
+	DefaultLanguage = Russian
+)
+
+// languages задает поддерживаемые языки в порядке приоритета
+var languages = []string{Russian, English}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Bundle - набор каталогов сообщений по языкам
+type Bundle struct {
+	catalogs map[string]map[string]string
+}
+
+var defaultBundle = mustLoadDefault()
+
+// Default возвращает встроенный набор каталогов
+func Default() *Bundle {
+	return defaultBundle
+}
+
+// Languages возвращает поддерживаемые языки
+func Languages() []string {
+	return append([]string(nil), languages...)
+}
+
+// IsSupported проверяет, есть ли каталог для языка
+func IsSupported(lang string) bool {
+	for _, l := range languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAcceptLanguage выбирает поддерживаемый язык из заголовка Accept-Language
+// с учетом весов q. Возвращает false, если подходящего языка нет
+func MatchAcceptLanguage(header string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return "", false
+	}
+
+	for _, tag := range tags {
+		base, confidence := tag.Base()
+		if confidence == language.No {
+			continue
+		}
+		if lang := base.String(); IsSupported(lang) {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+// Message возвращает шаблон сообщения на языке lang. Если перевода нет,
+// используется язык по умолчанию, если нет и его - сам ключ
+func (b *Bundle) Message(lang, key string) string {
+	if msg, ok := b.catalogs[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := b.catalogs[DefaultLanguage][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Has проверяет, есть ли сообщение с ключом в каталоге по умолчанию
+// (а значит, и в каталогах всех языков)
+func (b *Bundle) Has(key string) bool {
+	_, ok := b.catalogs[DefaultLanguage][key]
+	return ok
+}
+
+// Keys возвращает ключи каталога по умолчанию с префиксом prefix по алфавиту
+func (b *Bundle) Keys(prefix string) []string {
+	keys := make([]string, 0)
+	for key := range b.catalogs[DefaultLanguage] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Overlay возвращает набор каталогов, в котором сообщения messages
+// (язык -> ключ -> шаблон) заменяют встроенные. Учитываются только
+// поддерживаемые языки и ключи, которые есть в каталогах; остальные
+// сообщения берутся из b
+func (b *Bundle) Overlay(messages map[string]map[string]string) *Bundle {
+	overlay := &Bundle{catalogs: make(map[string]map[string]string, len(b.catalogs))}
+	for lang, catalog := range b.catalogs {
+		merged := make(map[string]string, len(catalog))
+		for key, msg := range catalog {
+			merged[key] = msg
+		}
+		for key, msg := range messages[lang] {
+			if _, ok := merged[key]; ok {
+				merged[key] = msg
+			}
+		}
+		overlay.catalogs[lang] = merged
+	}
+	return overlay
+}
+
+// Render подставляет параметры в сообщение на языке lang
+func (b *Bundle) Render(lang string, t Text) string {
+	if t.Key == "" {
+		return t.value
+	}
+
+	msg := b.Message(lang, t.Key)
+	for name, value := range t.Params {
+		var s string
+		switch v := value.(type) {
+		case Text:
+			s = b.Render(lang, v)
+		default:
+			s = fmt.Sprint(v)
+		}
+		msg = strings.ReplaceAll(msg, "{"+name+"}", s)
+	}
+	return msg
+}
+
+// ArcanaName возвращает локализуемое название аркана
+func ArcanaName(number int) Text {
+	if number < 1 || number > arcana.Count {
+		return T("arcana.unknown")
+	}
+	return T("arcana.name." + strconv.Itoa(number))
+}
+
+// ArcanaForecast возвращает локализуемую интерпретацию аркана для прогноза
+func ArcanaForecast(number int) Text {
+	if number < 1 || number > arcana.Count {
+		return Text{}
+	}
+	return T("arcana.forecast." + strconv.Itoa(number))
+}
+
+// ArcanaChild возвращает локализуемую задачу ребенка с родителем по аркану
+func ArcanaChild(number int) Text {
+	if number < 1 || number > arcana.Count {
+		return T("arcana.child.unknown", Params{"arcana": ArcanaName(number)})
+	}
+	return T("arcana.child." + strconv.Itoa(number))
+}
+
+// mustLoadDefault загружает встроенные каталоги и дополняет их данными
+// справочника арканов. Каталог каждого языка должен содержать все ключи
+// каталога по умолчанию
+func mustLoadDefault() *Bundle {
+	b := &Bundle{catalogs: make(map[string]map[string]string, len(languages))}
+
+	for _, lang := range languages {
+		data, err := localeFiles.ReadFile(path.Join("locales", lang+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog %s: %v", lang, err))
+		}
+
+		catalog := make(map[string]string)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: failed to parse catalog %s: %v", lang, err))
+		}
+		b.catalogs[lang] = catalog
+	}
+
+	// Названия арканов, тексты прогнозов и задач ребенка берутся из справочника
+	for _, a := range arcana.Default().All() {
+		n := strconv.Itoa(a.Number)
+		for _, lang := range languages {
+			b.catalogs[lang]["arcana.name."+n] = a.Title(lang)
+			b.catalogs[lang]["arcana.forecast."+n] = a.Forecast(lang)
+			b.catalogs[lang]["arcana.child."+n] = a.ChildTask(lang)
+		}
+	}
+
+	for _, lang := range languages {
+		for key := range b.catalogs[DefaultLanguage] {
+			if _, ok := b.catalogs[lang][key]; !ok {
+				panic(fmt.Sprintf("i18n: catalog %s has no message %q", lang, key))
+			}
+		}
+	}
+
+	return b
+}
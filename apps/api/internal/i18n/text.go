@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Params - параметры подстановки сообщения. Значением может быть Text,
+// тогда он отрисовывается на том же языке
+type Params map[string]interface{}
+
+// Text - локализуемая строка результата расчета. Калькуляторы заполняют ключ
+// каталога и параметры, а текст подставляется на границе обработчика через Localize.
+// Не отрисованный текст сериализуется на языке по умолчанию
+type Text struct {
+	Key    string
+	Params Params
+
+	value    string
+	rendered bool
+}
+
+// T создает локализуемую строку по ключу каталога
+func T(key string, params ...Params) Text {
+	t := Text{Key: key}
+	if len(params) > 0 {
+		t.Params = params[0]
+	}
+	return t
+}
+
+// Raw создает строку, которая не переводится (например, имя, введенное пользователем)
+func Raw(s string) Text {
+	return Text{value: s, rendered: true}
+}
+
+// String возвращает отрисованный текст или текст на языке по умолчанию
+func (t Text) String() string {
+	if t.rendered {
+		return t.value
+	}
+	return defaultBundle.Render(DefaultLanguage, t)
+}
+
+// MarshalJSON сериализует Text как обычную строку
+func (t Text) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+var textType = reflect.TypeOf(Text{})
+
+// Localize отрисовывает на языке lang все поля Text, достижимые из v.
+// v должен быть указателем, иначе поля не могут быть изменены
+func Localize(v interface{}, lang string) {
+	defaultBundle.Localize(v, lang)
+}
+
+// Localize отрисовывает на языке lang все поля Text, достижимые из v
+func (b *Bundle) Localize(v interface{}, lang string) {
+	b.localizeValue(reflect.ValueOf(v), lang)
+}
+
+func (b *Bundle) localizeValue(v reflect.Value, lang string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			b.localizeValue(v.Elem(), lang)
+		}
+	case reflect.Struct:
+		if v.Type() == textType {
+			if t := v.Interface().(Text); t.Key != "" && v.CanSet() {
+				t.value = b.Render(lang, t)
+				t.rendered = true
+				v.Set(reflect.ValueOf(t))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				b.localizeValue(v.Field(i), lang)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			b.localizeValue(v.Index(i), lang)
+		}
+	}
+}
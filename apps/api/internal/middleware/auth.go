@@ -3,8 +3,11 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"arcanum/internal/config"
+	"arcanum/internal/database"
+	"arcanum/internal/models"
 	"arcanum/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -48,6 +51,53 @@ func JWTAuth(cfg *config.JWTConfig) gin.HandlerFunc {
 	}
 }
 
+// OptionalJWTAuth сохраняет данные пользователя в контексте, если передан валидный токен.
+// Запросы без токена пропускаются как анонимные
+func OptionalJWTAuth(cfg *config.JWTConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateToken(parts[1], cfg.Secret); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("email", claims.Email)
+			}
+		}
+		c.Next()
+	}
+}
+
+// LoadPremiumStatus загружает статус Premium подписки пользователя в контекст.
+// Должен идти после JWTAuth
+func LoadPremiumStatus(userRepo *database.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.Set("isPremium", false)
+			c.Next()
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID.(string))
+		if err != nil {
+			if err == database.ErrUserNotFound {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+			}
+			c.Abort()
+			return
+		}
+
+		isPremium := user.IsPremium
+		if isPremium && user.PremiumExpiresAt != nil && time.Now().After(*user.PremiumExpiresAt) {
+			isPremium = false
+		}
+
+		c.Set("isPremium", isPremium)
+		c.Next()
+	}
+}
+
 // RequirePremium проверяет наличие Premium подписки
 func RequirePremium() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -63,3 +113,35 @@ func RequirePremium() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireAdmin проверяет, что пользователь является администратором.
+// Должен идти после JWTAuth
+func RequireAdmin(userRepo *database.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID.(string))
+		if err != nil {
+			if err == database.ErrUserNotFound {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+			}
+			c.Abort()
+			return
+		}
+
+		if user.Role != models.UserRoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
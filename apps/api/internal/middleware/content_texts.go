@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"arcanum/internal/services/content"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentTexts подключает опубликованные в CMS тексты к локализации ответов.
+// Каталоги загружаются только при локализации ответа, а не в каждом запросе
+func ContentTexts(texts *content.Texts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("contentTexts", texts)
+		c.Next()
+	}
+}
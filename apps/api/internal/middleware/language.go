@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Language выбирает язык ответа: из Accept-Language, затем из настроек пользователя
+// (если запрос авторизован), иначе язык по умолчанию. Должен идти после JWTAuth или OptionalJWTAuth
+func Language(userRepo *database.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang, ok := i18n.MatchAcceptLanguage(c.GetHeader("Accept-Language"))
+		if !ok {
+			lang = i18n.DefaultLanguage
+			if userID, exists := c.Get("userID"); exists {
+				user, err := userRepo.FindByID(c.Request.Context(), userID.(string))
+				if err == nil && i18n.IsSupported(user.Language) {
+					lang = user.Language
+				}
+			}
+		}
+
+		c.Set("language", lang)
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
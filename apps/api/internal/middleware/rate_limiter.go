@@ -6,8 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	"arcanum/internal/database"
+
 	"github.com/gin-gonic/gin"
-	"github.com/akhludeyev/arcanum/api/internal/database"
 )
 
 // RateLimiter middleware для ограничения количества запросов
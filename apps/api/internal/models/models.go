@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -9,28 +10,120 @@ type User struct {
 	Email            string     `json:"email" db:"email"`
 	PasswordHash     string     `json:"-" db:"password_hash"`
 	Name             string     `json:"name" db:"name"`
+	Role             UserRole   `json:"role" db:"role"`
+	Language         string     `json:"language" db:"language"`
+	BirthDate        *string    `json:"birthDate,omitempty" db:"birth_date"` // DD.MM.YYYY
+	Timezone         string     `json:"timezone" db:"timezone"`
 	IsPremium        bool       `json:"isPremium" db:"is_premium"`
 	PremiumExpiresAt *time.Time `json:"premiumExpiresAt,omitempty" db:"premium_expires_at"`
 	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updatedAt" db:"updated_at"`
 }
 
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
 type CalculationType string
 
 const (
-	CalculationTypeMatrix        CalculationType = "matrix"
-	CalculationTypePythagoras    CalculationType = "pythagoras"
-	CalculationTypeCompatibility CalculationType = "compatibility"
-	CalculationTypeChildRole     CalculationType = "child_role"
+	CalculationTypeMatrix             CalculationType = "matrix"
+	CalculationTypePythagoras         CalculationType = "pythagoras"
+	CalculationTypeCompatibility      CalculationType = "compatibility"
+	CalculationTypeChildRole          CalculationType = "child_role"
+	CalculationTypeAgeTimeline        CalculationType = "age_timeline"
+	CalculationTypeNameNumber         CalculationType = "name_number"
+	CalculationTypeGroupCompatibility CalculationType = "group_compatibility"
+	CalculationTypeHealthMap          CalculationType = "health_map"
+	CalculationTypeForecasts          CalculationType = "forecasts"
+	CalculationTypeDay                CalculationType = "day"
+	CalculationTypeFavorableDates     CalculationType = "favorable_dates"
 )
 
 type Calculation struct {
-	ID         string          `json:"id" db:"id"`
-	UserID     string          `json:"userId" db:"user_id"`
-	Type       CalculationType `json:"type" db:"type"`
-	InputData  interface{}     `json:"inputData" db:"input_data"`
-	ResultData interface{}     `json:"resultData" db:"result_data"`
-	CreatedAt  time.Time       `json:"createdAt" db:"created_at"`
+	ID               string          `json:"id" db:"id"`
+	UserID           string          `json:"userId" db:"user_id"`
+	Type             CalculationType `json:"type" db:"type"`
+	AlgorithmVersion int             `json:"algorithmVersion" db:"algorithm_version"`
+	Language         string          `json:"language" db:"language"`
+	InputData        interface{}     `json:"inputData" db:"input_data"`
+	ResultData       interface{}     `json:"resultData" db:"result_data"`
+	CreatedAt        time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// ArcanaInterpretation - локализованный текст аркана (таблица arcana_interpretations)
+type ArcanaInterpretation struct {
+	ArcanaNumber     int       `json:"arcanaNumber" db:"arcana_number"`
+	Language         string    `json:"language" db:"language"`
+	Title            string    `json:"title" db:"title"`
+	ShortDescription string    `json:"shortDescription" db:"short_description"`
+	FullDescription  string    `json:"fullDescription" db:"full_description"`
+	Plus             []string  `json:"plus" db:"plus_aspects"`
+	Minus            []string  `json:"minus" db:"minus_aspects"`
+	Love             string    `json:"love" db:"love_aspect"`
+	Money            string    `json:"money" db:"money_aspect"`
+	Health           string    `json:"health" db:"health_aspect"`
+	Child            string    `json:"child" db:"child_aspect"`
+	Recommendations  []string  `json:"recommendations" db:"recommendations"`
+	UpdatedAt        time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// PythagorasElementInterpretation - локализованные тексты элемента психоматрицы
+// (таблица pythagoras_interpretations). Texts - текст для каждой градации (absent, weak, ...)
+type PythagorasElementInterpretation struct {
+	Element   string            `json:"element" db:"element"`
+	Key       string            `json:"key" db:"element_key"`
+	Language  string            `json:"language" db:"language"`
+	Name      string            `json:"name" db:"name"`
+	Texts     map[string]string `json:"texts" db:"texts"`
+	UpdatedAt time.Time         `json:"updatedAt" db:"updated_at"`
+}
+
+// CompatibilityText - опубликованный текст совместимости (таблица compatibility_texts).
+// Key - ключ сообщения без префикса compatibility., например strength.spiritual
+type CompatibilityText struct {
+	Key       string    `json:"key" db:"text_key"`
+	Language  string    `json:"language" db:"language"`
+	Text      string    `json:"text" db:"text"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+type ContentType string
+
+const (
+	ContentTypeArcana        ContentType = "arcana"
+	ContentTypePythagoras    ContentType = "pythagoras"
+	ContentTypeCompatibility ContentType = "compatibility"
+)
+
+type ContentStatus string
+
+const (
+	ContentStatusDraft     ContentStatus = "draft"
+	ContentStatusReview    ContentStatus = "review"
+	ContentStatusPublished ContentStatus = "published"
+	ContentStatusArchived  ContentStatus = "archived"
+)
+
+// ContentRevision - ревизия справочного текста. Data содержит ArcanaInterpretation,
+// PythagorasElementInterpretation или CompatibilityText в зависимости от типа контента
+type ContentRevision struct {
+	ID          string          `json:"id" db:"id"`
+	ContentType ContentType     `json:"contentType" db:"content_type"`
+	ContentKey  string          `json:"contentKey" db:"content_key"`
+	Language    string          `json:"language" db:"language"`
+	Revision    int             `json:"revision" db:"revision"`
+	Status      ContentStatus   `json:"status" db:"status"`
+	Data        json.RawMessage `json:"data" db:"data"`
+	Comment     string          `json:"comment,omitempty" db:"comment"`
+	AuthorID    *string         `json:"authorId,omitempty" db:"author_id"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	SubmittedAt *time.Time      `json:"submittedAt,omitempty" db:"submitted_at"`
+	PublishedBy *string         `json:"publishedBy,omitempty" db:"published_by"`
+	PublishedAt *time.Time      `json:"publishedAt,omitempty" db:"published_at"`
 }
 
 type SubscriptionStatus string
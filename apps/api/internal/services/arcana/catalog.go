@@ -0,0 +1,140 @@
+package arcana
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// Count - количество старших арканов
+	Count = 22
+
+	// DefaultLanguage - язык, используемый, если перевод отсутствует
+	DefaultLanguage = "ru"
+)
+
+// UnknownName возвращается для номеров вне диапазона 1-22
+const UnknownName = "Неизвестный аркан"
+
+//go:embed data/arcanas.json
+var catalogData []byte
+
+// Arcana представляет справочные данные одного аркана
+type Arcana struct {
+	Number          int               `json:"number"`
+	Titles          map[string]string `json:"titles"`
+	Keywords        []string          `json:"keywords"`
+	Plus            []string          `json:"plus"`
+	Minus           []string          `json:"minus"`
+	Love            string            `json:"love"`
+	Money           string            `json:"money"`
+	Health          string            `json:"health"`
+	ChildTasks      map[string]string `json:"childTasks"`
+	Forecasts       map[string]string `json:"forecasts"`
+	Recommendations []string          `json:"recommendations"`
+}
+
+// Title возвращает название аркана на указанном языке (с откатом на русский)
+func (a *Arcana) Title(lang string) string {
+	if title, ok := a.Titles[lang]; ok && title != "" {
+		return title
+	}
+	return a.Titles[DefaultLanguage]
+}
+
+// ChildTask возвращает задачу ребенка с родителем на указанном языке (с откатом на русский)
+func (a *Arcana) ChildTask(lang string) string {
+	if text, ok := a.ChildTasks[lang]; ok && text != "" {
+		return text
+	}
+	return a.ChildTasks[DefaultLanguage]
+}
+
+// Forecast возвращает интерпретацию аркана для прогноза на указанном языке (с откатом на русский)
+func (a *Arcana) Forecast(lang string) string {
+	if text, ok := a.Forecasts[lang]; ok && text != "" {
+		return text
+	}
+	return a.Forecasts[DefaultLanguage]
+}
+
+// Catalog - справочник 22 арканов
+type Catalog struct {
+	arcanas [Count + 1]*Arcana
+}
+
+// Load разбирает справочник из JSON и проверяет, что описаны все 22 аркана
+func Load(data []byte) (*Catalog, error) {
+	var list []*Arcana
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse arcana catalog: %w", err)
+	}
+
+	catalog := &Catalog{}
+	for _, a := range list {
+		if a.Number < 1 || a.Number > Count {
+			return nil, fmt.Errorf("arcana number out of range: %d", a.Number)
+		}
+		if catalog.arcanas[a.Number] != nil {
+			return nil, fmt.Errorf("duplicate arcana: %d", a.Number)
+		}
+		if a.Titles[DefaultLanguage] == "" {
+			return nil, fmt.Errorf("arcana %d has no %s title", a.Number, DefaultLanguage)
+		}
+		if a.ChildTasks[DefaultLanguage] == "" || a.Forecasts[DefaultLanguage] == "" {
+			return nil, fmt.Errorf("arcana %d has no %s child task or forecast", a.Number, DefaultLanguage)
+		}
+		catalog.arcanas[a.Number] = a
+	}
+
+	for n := 1; n <= Count; n++ {
+		if catalog.arcanas[n] == nil {
+			return nil, fmt.Errorf("arcana %d is missing", n)
+		}
+	}
+
+	return catalog, nil
+}
+
+// defaultCatalog загружается один раз при старте из встроенного файла
+var defaultCatalog = mustLoad(catalogData)
+
+func mustLoad(data []byte) *Catalog {
+	catalog, err := Load(data)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Default возвращает встроенный справочник
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Get возвращает аркан по номеру
+func (c *Catalog) Get(number int) (*Arcana, bool) {
+	if number < 1 || number > Count {
+		return nil, false
+	}
+	return c.arcanas[number], true
+}
+
+// All возвращает все арканы по порядку номеров
+func (c *Catalog) All() []*Arcana {
+	return append([]*Arcana(nil), c.arcanas[1:]...)
+}
+
+// Get возвращает аркан из встроенного справочника
+func Get(number int) (*Arcana, bool) {
+	return defaultCatalog.Get(number)
+}
+
+// Name возвращает русское название аркана по номеру
+func Name(number int) string {
+	if a, ok := defaultCatalog.Get(number); ok {
+		return a.Title(DefaultLanguage)
+	}
+	return UnknownName
+}
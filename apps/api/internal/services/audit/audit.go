@@ -0,0 +1,212 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+	"arcanum/internal/models"
+	"arcanum/internal/services/calculator"
+)
+
+// pageSize - количество расчетов, загружаемых из БД за один запрос
+const pageSize = 200
+
+// Difference представляет расхождение значения по JSON пути
+type Difference struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// Entry представляет результат сравнения одного расчета
+type Entry struct {
+	CalculationID string       `json:"calculationId"`
+	StoredVersion int          `json:"storedVersion"`
+	Error         string       `json:"error,omitempty"`
+	Differences   []Difference `json:"differences,omitempty"`
+}
+
+// Report представляет итог аудита изменений формулы
+type Report struct {
+	Type        models.CalculationType `json:"type"`
+	FromVersion int                    `json:"fromVersion"`
+	ToVersion   int                    `json:"toVersion"`
+	Total       int                    `json:"total"`
+	Changed     int                    `json:"changed"`
+	Failed      int                    `json:"failed"`
+	Entries     []Entry                `json:"entries"`
+}
+
+// Auditor пересчитывает сохраненные расчеты новой версией алгоритма и сравнивает результаты
+type Auditor struct {
+	calcRepo *database.CalculationRepository
+	registry *calculator.Registry
+}
+
+func NewAuditor(calcRepo *database.CalculationRepository, registry *calculator.Registry) *Auditor {
+	return &Auditor{
+		calcRepo: calcRepo,
+		registry: registry,
+	}
+}
+
+// Run сравнивает результаты версий fromVersion и toVersion для всех расчетов типа.
+// fromVersion = 0 означает сравнение с сохраненным результатом, toVersion = 0 - последнюю версию.
+// В отчет попадают только расчеты с расхождениями или ошибками
+func (a *Auditor) Run(ctx context.Context, calcType models.CalculationType, fromVersion, toVersion int) (*Report, error) {
+	to, err := a.registry.Get(calcType, toVersion)
+	if err != nil {
+		return nil, fmt.Errorf("target version: %w", err)
+	}
+
+	var from calculator.Calculator
+	if fromVersion != 0 {
+		from, err = a.registry.Get(calcType, fromVersion)
+		if err != nil {
+			return nil, fmt.Errorf("source version: %w", err)
+		}
+	}
+
+	report := &Report{
+		Type:        calcType,
+		FromVersion: fromVersion,
+		ToVersion:   to.Version(),
+		Entries:     make([]Entry, 0),
+	}
+
+	afterID := ""
+	for {
+		calculations, err := a.calcRepo.ListByType(ctx, calcType, afterID, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(calculations) == 0 {
+			break
+		}
+
+		for _, calc := range calculations {
+			report.Total++
+			entry := a.compare(calc, from, to)
+			if entry.Error != "" {
+				report.Failed++
+			} else if len(entry.Differences) > 0 {
+				report.Changed++
+			} else {
+				continue
+			}
+			report.Entries = append(report.Entries, entry)
+		}
+
+		afterID = calculations[len(calculations)-1].ID
+	}
+
+	return report, nil
+}
+
+// compare пересчитывает один расчет и сравнивает результаты. Сохраненный результат
+// содержит тексты на языке запроса, поэтому пересчет отрисовывается на том же языке
+func (a *Auditor) compare(calc *models.Calculation, from, to calculator.Calculator) Entry {
+	entry := Entry{
+		CalculationID: calc.ID,
+		StoredVersion: calc.AlgorithmVersion,
+	}
+
+	input, err := json.Marshal(calc.InputData)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	oldResult := calc.ResultData
+	if from != nil {
+		result, err := from.Calculate(input)
+		if err != nil {
+			entry.Error = fmt.Sprintf("version %d: %v", from.Version(), err)
+			return entry
+		}
+		if oldResult, err = normalize(result, calc.Language); err != nil {
+			entry.Error = err.Error()
+			return entry
+		}
+	}
+
+	result, err := to.Calculate(input)
+	if err != nil {
+		entry.Error = fmt.Sprintf("version %d: %v", to.Version(), err)
+		return entry
+	}
+	newResult, err := normalize(result, calc.Language)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.Differences = Diff(oldResult, newResult)
+	return entry
+}
+
+// normalize отрисовывает тексты результата на языке lang встроенными каталогами
+// и приводит результат к JSON представлению (map/slice/float64)
+func normalize(v interface{}, lang string) (interface{}, error) {
+	i18n.Localize(v, lang)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Diff сравнивает два JSON значения и возвращает расхождения по путям
+func Diff(oldValue, newValue interface{}) []Difference {
+	diffs := make([]Difference, 0)
+	diff("$", oldValue, newValue, &diffs)
+	return diffs
+}
+
+func diff(path string, oldValue, newValue interface{}, diffs *[]Difference) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make(map[string]struct{}, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys[k] = struct{}{}
+		}
+		for k := range newMap {
+			keys[k] = struct{}{}
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			diff(path+"."+k, oldMap[k], newMap[k], diffs)
+		}
+		return
+	}
+
+	oldSlice, oldIsSlice := oldValue.([]interface{})
+	newSlice, newIsSlice := newValue.([]interface{})
+	if oldIsSlice && newIsSlice && len(oldSlice) == len(newSlice) {
+		for i := range oldSlice {
+			diff(fmt.Sprintf("%s[%d]", path, i), oldSlice[i], newSlice[i], diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*diffs = append(*diffs, Difference{Path: path, Old: oldValue, New: newValue})
+	}
+}
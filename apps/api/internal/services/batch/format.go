@@ -0,0 +1,60 @@
+package batch
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+)
+
+// Format - формат входных и выходных данных пакетного расчета
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// ContentType возвращает MIME тип формата для ответа
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// FormatFromContentType определяет формат по заголовку Content-Type
+func FormatFromContentType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q", contentType)
+	}
+
+	switch mediaType {
+	case "application/json":
+		return FormatJSON, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/jsonlines":
+		return FormatNDJSON, nil
+	case "text/csv", "application/csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unsupported content type %q: expected application/json, application/x-ndjson or text/csv", mediaType)
+}
+
+// FormatFromFilename определяет формат загруженного файла по расширению
+func FormatFromFilename(filename string) (Format, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unsupported file %q: expected .json, .ndjson, .jsonl or .csv", filename)
+}
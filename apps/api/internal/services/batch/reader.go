@@ -0,0 +1,257 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxLineBytes - максимальная длина одной строки NDJSON
+const MaxLineBytes = 1 << 20
+
+// Item - входные данные одной строки пакета
+type Item struct {
+	// Input - входные данные расчета в JSON формате запросов API
+	Input json.RawMessage
+	// Fields - исходные ячейки строки CSV (для остальных форматов nil)
+	Fields []string
+}
+
+// RowError - ошибка разбора одной строки. Чтение пакета можно продолжить
+type RowError struct {
+	Err error
+}
+
+func (e *RowError) Error() string { return e.Err.Error() }
+
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Reader читает входные данные пакета по одной строке, не загружая их целиком.
+// Next возвращает io.EOF после последней строки, *RowError - если строку нельзя
+// разобрать, но чтение можно продолжить; любая другая ошибка прерывает пакет
+type Reader interface {
+	Next() (Item, error)
+	// Columns возвращает заголовок CSV (для остальных форматов nil)
+	Columns() []string
+}
+
+// NewReader создает чтение пакета в формате format
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatJSON:
+		return newJSONReader(r)
+	case FormatNDJSON:
+		return newNDJSONReader(r), nil
+	case FormatCSV:
+		return newCSVReader(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// jsonReader читает элементы JSON массива потоково
+type jsonReader struct {
+	dec *json.Decoder
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("invalid JSON: expected an array of inputs")
+	}
+	return &jsonReader{dec: dec}, nil
+}
+
+func (r *jsonReader) Next() (Item, error) {
+	if !r.dec.More() {
+		if _, err := r.dec.Token(); err != nil {
+			return Item{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return Item{}, io.EOF
+	}
+
+	var input json.RawMessage
+	if err := r.dec.Decode(&input); err != nil {
+		return Item{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := checkObject(input); err != nil {
+		return Item{}, &RowError{Err: err}
+	}
+	return Item{Input: input}, nil
+}
+
+func (r *jsonReader) Columns() []string { return nil }
+
+// ndjsonReader читает по одному JSON объекту на строку. Пустые строки пропускаются
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineBytes)
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) Next() (Item, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if !json.Valid(line) {
+			// Строка оборвана ошибкой чтения - продолжать нельзя
+			if err := r.scanner.Err(); err != nil {
+				return Item{}, err
+			}
+			return Item{}, &RowError{Err: errors.New("invalid JSON line")}
+		}
+		input := json.RawMessage(append([]byte(nil), line...))
+		if err := checkObject(input); err != nil {
+			return Item{}, &RowError{Err: err}
+		}
+		return Item{Input: input}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return Item{}, fmt.Errorf("line exceeds %d bytes", MaxLineBytes)
+		}
+		return Item{}, err
+	}
+	return Item{}, io.EOF
+}
+
+func (r *ndjsonReader) Columns() []string { return nil }
+
+// csvReader читает CSV с заголовком. Колонки задают поля входных данных,
+// вложенные поля записываются через точку: person1.birthDate, people.0.birthDate
+type csvReader struct {
+	reader  *csv.Reader
+	columns []string
+	paths   [][]string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("invalid CSV: header row is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	paths := make([][]string, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if column == "" {
+			return nil, fmt.Errorf("invalid CSV: column %d has no name", i+1)
+		}
+		columns[i] = column
+		paths[i] = strings.Split(column, ".")
+	}
+	return &csvReader{reader: reader, columns: columns, paths: paths}, nil
+}
+
+func (r *csvReader) Next() (Item, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return Item{}, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Item{Fields: record}, &RowError{Err: fmt.Errorf("invalid CSV row: %w", parseErr.Err)}
+	}
+	if err != nil {
+		return Item{}, err
+	}
+
+	if len(record) != len(r.columns) {
+		return Item{Fields: record}, &RowError{Err: fmt.Errorf("expected %d columns, got %d", len(r.columns), len(record))}
+	}
+
+	input := map[string]interface{}{}
+	for i, value := range record {
+		if value == "" {
+			continue
+		}
+		if err := setPath(input, r.paths[i], value); err != nil {
+			return Item{Fields: record}, &RowError{Err: fmt.Errorf("column %s: %w", r.columns[i], err)}
+		}
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return Item{Fields: record}, &RowError{Err: err}
+	}
+	return Item{Input: data, Fields: record}, nil
+}
+
+func (r *csvReader) Columns() []string { return r.columns }
+
+// setPath записывает значение по пути колонки. Числовой сегмент пути - номер
+// элемента списка (с нуля)
+func setPath(obj map[string]interface{}, path []string, value string) error {
+	key := path[0]
+	if len(path) == 1 {
+		obj[key] = value
+		return nil
+	}
+
+	if index, err := strconv.Atoi(path[1]); err == nil {
+		if index < 0 || index > 99 {
+			return fmt.Errorf("list index must be between 0 and 99")
+		}
+		list, ok := obj[key].([]interface{})
+		if _, exists := obj[key]; exists && !ok {
+			return fmt.Errorf("conflicting columns")
+		}
+		for len(list) <= index {
+			list = append(list, map[string]interface{}{})
+		}
+		obj[key] = list
+
+		item, ok := list[index].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("conflicting columns")
+		}
+		if len(path) == 2 {
+			return fmt.Errorf("list items must be objects")
+		}
+		return setPath(item, path[2:], value)
+	}
+
+	child, ok := obj[key].(map[string]interface{})
+	if !ok {
+		if _, exists := obj[key]; exists {
+			return fmt.Errorf("conflicting columns")
+		}
+		child = map[string]interface{}{}
+		obj[key] = child
+	}
+	return setPath(child, path[1:], value)
+}
+
+// checkObject проверяет, что входные данные строки - JSON объект
+func checkObject(input json.RawMessage) error {
+	if len(input) == 0 || input[0] != '{' {
+		return errors.New("input must be a JSON object")
+	}
+	return nil
+}
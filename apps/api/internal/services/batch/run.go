@@ -0,0 +1,80 @@
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CalculateFunc рассчитывает одну строку пакета
+type CalculateFunc func(input json.RawMessage) (interface{}, error)
+
+// Summary - итог обработки пакета
+type Summary struct {
+	Rows      int
+	Failed    int
+	Truncated bool
+}
+
+// Run читает строки из reader, рассчитывает каждую и сразу записывает
+// результат в writer, поэтому в памяти находится только одна строка.
+// Ошибка строки записывается в ее результат и не прерывает пакет. Если строк
+// больше maxRows или входные данные нельзя читать дальше, последней
+// записывается строка с ошибкой. Возвращаемая ошибка - ошибка записи ответа
+func Run(reader Reader, writer Writer, maxRows int, calculate CalculateFunc, flush func()) (Summary, error) {
+	var summary Summary
+	for {
+		item, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		row := summary.Rows + 1
+		if row > maxRows {
+			summary.Truncated = true
+			if err := writer.Write(Result{Row: row, Error: fmt.Sprintf("batch size limit of %d rows exceeded", maxRows)}); err != nil {
+				return summary, err
+			}
+			break
+		}
+		summary.Rows = row
+
+		var rowErr *RowError
+		if err != nil && !errors.As(err, &rowErr) {
+			summary.Failed++
+			summary.Truncated = true
+			if err := writer.Write(Result{Row: row, Error: err.Error(), Fields: item.Fields}); err != nil {
+				return summary, err
+			}
+			break
+		}
+
+		result := Result{Row: row, Fields: item.Fields}
+		if rowErr != nil {
+			result.Error = rowErr.Error()
+		} else if data, err := calculate(item.Input); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Data = data
+		}
+		if result.Error != "" {
+			summary.Failed++
+		}
+
+		if err := writer.Write(result); err != nil {
+			return summary, err
+		}
+		if flush != nil {
+			flush()
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return summary, err
+	}
+	if flush != nil {
+		flush()
+	}
+	return summary, nil
+}
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Result - результат расчета одной строки пакета
+type Result struct {
+	Row    int
+	Data   interface{}
+	Error  string
+	Fields []string
+}
+
+// Writer записывает результаты пакета по мере расчета
+type Writer interface {
+	Write(result Result) error
+	// Close завершает ответ (закрывает JSON массив)
+	Close() error
+}
+
+// NewWriter создает запись результатов в формате format. Для CSV columns -
+// заголовок входного файла: исходные ячейки повторяются в ответе
+func NewWriter(format Format, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w}, nil
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// resultLine - строка результата в JSON форматах
+type resultLine struct {
+	Row     int         `json:"row"`
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+func newResultLine(result Result) resultLine {
+	return resultLine{
+		Row:     result.Row,
+		Success: result.Error == "",
+		Data:    result.Data,
+		Error:   result.Error,
+	}
+}
+
+// jsonWriter записывает результаты элементами JSON массива
+type jsonWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (w *jsonWriter) Write(result Result) error {
+	data, err := json.Marshal(newResultLine(result))
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if !w.started {
+		prefix = "[\n"
+		w.started = true
+	}
+	if _, err := io.WriteString(w.w, prefix); err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	suffix := "\n]\n"
+	if !w.started {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(w.w, suffix)
+	return err
+}
+
+// ndjsonWriter записывает по одному JSON объекту на строку
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+func (w *ndjsonWriter) Write(result Result) error {
+	data, err := json.Marshal(newResultLine(result))
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
+
+func (w *ndjsonWriter) Close() error { return nil }
+
+// csvWriter записывает номер строки, исходные ячейки, признак успеха, ошибку
+// и результат расчета в JSON
+type csvWriter struct {
+	w       *csv.Writer
+	columns int
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	header := append(append([]string{"row"}, columns...), "success", "error", "result")
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+	return &csvWriter{w: writer, columns: len(columns)}, writer.Error()
+}
+
+func (w *csvWriter) Write(result Result) error {
+	record := make([]string, 0, w.columns+4)
+	record = append(record, strconv.Itoa(result.Row))
+	for i := 0; i < w.columns; i++ {
+		value := ""
+		if i < len(result.Fields) {
+			value = result.Fields[i]
+		}
+		record = append(record, value)
+	}
+
+	data := ""
+	if result.Data != nil {
+		encoded, err := json.Marshal(result.Data)
+		if err != nil {
+			return err
+		}
+		data = string(encoded)
+	}
+	record = append(record, strconv.FormatBool(result.Error == ""), result.Error, data)
+
+	if err := w.w.Write(record); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
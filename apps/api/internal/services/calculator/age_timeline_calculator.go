@@ -0,0 +1,176 @@
+package calculator
+
+import (
+	"fmt"
+
+	"arcanum/internal/i18n"
+)
+
+const (
+	// TimelineMaxAge - возраст, на котором замыкается периметр матрицы
+	TimelineMaxAge = 80
+
+	// timelineEdgeYears - количество лет на одном ребре периметра
+	timelineEdgeYears = 10
+
+	// timelineSegmentYears - длительность одного сегмента линии возраста
+	timelineSegmentYears = 1.25
+)
+
+// AgeTimeline представляет линию возраста по периметру матрицы (0-80 лет)
+type AgeTimeline struct {
+	BirthDate BirthDate    `json:"birthDate"`
+	Segments  []AgeSegment `json:"segments"`
+}
+
+// AgeSegment представляет период жизни с активной программой
+type AgeSegment struct {
+	StartAge   float64   `json:"startAge"`
+	EndAge     float64   `json:"endAge"`
+	Arcana     int       `json:"arcana"`
+	ArcanaName i18n.Text `json:"arcanaName"`
+}
+
+// AgeTimelineReport - полный результат /calculate/age-timeline: линия возраста
+// и активный сегмент, если запрошен возраст или дата
+type AgeTimelineReport struct {
+	Timeline *AgeTimeline `json:"timeline"`
+	Active   *AgeSegment  `json:"active,omitempty"`
+}
+
+// timelinePerimeter задает обход периметра матрицы по часовой стрелке от точки A
+var timelinePerimeter = []string{
+	PointDay, PointTopLeft, PointMonth, PointTopRight,
+	PointYear, PointBottomRight, PointBottom, PointBottomLeft,
+}
+
+// CalculateAgeTimeline рассчитывает линию возраста от рождения до 80 лет
+func CalculateAgeTimeline(birthDate BirthDate) (*AgeTimeline, error) {
+	return CalculateAgeTimelineWithTrace(birthDate, nil)
+}
+
+// CalculateAgeTimelineWithTrace рассчитывает линию возраста и записывает
+// в trace точки матрицы и деление каждого ребра периметра
+func CalculateAgeTimelineWithTrace(birthDate BirthDate, trace *Trace) (*AgeTimeline, error) {
+	matrix, err := CalculateFullMatrixWithTrace(birthDate, trace.Scope("fullMatrix"))
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]AgeSegment, 0, len(timelinePerimeter)*8)
+	for i, id := range timelinePerimeter {
+		endID := timelinePerimeter[(i+1)%len(timelinePerimeter)]
+		start, _ := matrix.Point(id)
+		end, _ := matrix.Point(endID)
+
+		// Сегменты ребра нумеруются сквозь весь периметр
+		first := i * 8
+		name := func(j int) string {
+			return fmt.Sprintf("segments[%d]", first+j)
+		}
+		segment := func(j, value int) TraceOperand {
+			return plus(name(j), value)
+		}
+
+		edge := make([]int, 8)
+		edge[0] = trace.assign(name(0), plus(id, start.Arcana))
+
+		// Каждое ребро делится пополам, затем каждая половина - еще дважды
+		edge[4] = trace.reduceSum(name(4), plus(id, start.Arcana), plus(endID, end.Arcana))
+		edge[2] = trace.reduceSum(name(2), segment(0, edge[0]), segment(4, edge[4]))
+		edge[6] = trace.reduceSum(name(6), segment(4, edge[4]), plus(endID, end.Arcana))
+
+		edge[1] = trace.reduceSum(name(1), segment(0, edge[0]), segment(2, edge[2]))
+		edge[3] = trace.reduceSum(name(3), segment(2, edge[2]), segment(4, edge[4]))
+		edge[5] = trace.reduceSum(name(5), segment(4, edge[4]), segment(6, edge[6]))
+		edge[7] = trace.reduceSum(name(7), segment(6, edge[6]), plus(endID, end.Arcana))
+
+		edgeStart := float64(i * timelineEdgeYears)
+		for j, arcana := range edge {
+			segmentStart := edgeStart + float64(j)*timelineSegmentYears
+			segments = append(segments, AgeSegment{
+				StartAge:   segmentStart,
+				EndAge:     segmentStart + timelineSegmentYears,
+				Arcana:     arcana,
+				ArcanaName: GetArcanaName(arcana),
+			})
+		}
+	}
+
+	return &AgeTimeline{
+		BirthDate: birthDate,
+		Segments:  segments,
+	}, nil
+}
+
+// CalculateAgeTimelineReport рассчитывает линию возраста и активный сегмент.
+// Дата (DD.MM.YYYY) имеет приоритет над возрастом; без них Active не заполняется
+func CalculateAgeTimelineReport(birthDate BirthDate, age *float64, date string) (*AgeTimelineReport, error) {
+	return CalculateAgeTimelineReportWithTrace(birthDate, age, date, nil)
+}
+
+// CalculateAgeTimelineReportWithTrace рассчитывает линию возраста с активным сегментом и записывает шаги в trace
+func CalculateAgeTimelineReportWithTrace(birthDate BirthDate, age *float64, date string, trace *Trace) (*AgeTimelineReport, error) {
+	timeline, err := CalculateAgeTimelineWithTrace(birthDate, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AgeTimelineReport{Timeline: timeline}
+
+	var active AgeSegment
+	switch {
+	case date != "":
+		active, err = timeline.AtDate(date)
+	case age != nil:
+		active, err = timeline.At(*age)
+	default:
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Active = &active
+
+	return report, nil
+}
+
+// At возвращает сегмент, активный в указанном возрасте (в годах)
+func (t *AgeTimeline) At(age float64) (AgeSegment, error) {
+	if age < 0 || age >= TimelineMaxAge {
+		return AgeSegment{}, fmt.Errorf("age must be between 0 and %d", TimelineMaxAge)
+	}
+
+	for _, segment := range t.Segments {
+		if age >= segment.StartAge && age < segment.EndAge {
+			return segment, nil
+		}
+	}
+	return AgeSegment{}, fmt.Errorf("no segment found for age %.2f", age)
+}
+
+// AtDate возвращает сегмент, активный на указанную дату (в любом формате ParseDate)
+func (t *AgeTimeline) AtDate(date string) (AgeSegment, error) {
+	target, err := ParseDate(date)
+	if err != nil {
+		return AgeSegment{}, err
+	}
+
+	birth := t.BirthDate.Time()
+	birthYear := birth.Year()
+	if target.Before(birth) {
+		return AgeSegment{}, fmt.Errorf("date must not be before birth date")
+	}
+
+	// Полные годы плюс доля прошедшего года с последнего дня рождения
+	years := target.Year() - birthYear
+	lastBirthday := birth.AddDate(years, 0, 0)
+	if lastBirthday.After(target) {
+		years--
+		lastBirthday = birth.AddDate(years, 0, 0)
+	}
+	nextBirthday := birth.AddDate(years+1, 0, 0)
+	fraction := target.Sub(lastBirthday).Hours() / nextBirthday.Sub(lastBirthday).Hours()
+
+	return t.At(float64(years) + fraction)
+}
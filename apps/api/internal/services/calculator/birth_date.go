@@ -0,0 +1,251 @@
+package calculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMinBirthYear - минимальный год рождения по умолчанию
+const DefaultMinBirthYear = 1900
+
+// birthDateLayout - канонический формат даты рождения в ответах API
+const birthDateLayout = "02.01.2006"
+
+// minBirthYear задается один раз при старте сервера из конфигурации
+var minBirthYear = DefaultMinBirthYear
+
+// SetMinBirthYear задает минимальный допустимый год рождения
+func SetMinBirthYear(year int) {
+	minBirthYear = year
+}
+
+// MinBirthYear возвращает минимальный допустимый год рождения
+func MinBirthYear() int {
+	return minBirthYear
+}
+
+// ErrEmptyBirthDate возвращается, если дата рождения не указана
+var ErrEmptyBirthDate = errors.New("birth date is required")
+
+// DateError описывает ошибку разбора даты рождения
+type DateError struct {
+	Value  string
+	Reason string
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("invalid birth date %q: %s", e.Value, e.Reason)
+}
+
+// BirthDate представляет проверенную календарную дату рождения.
+// В JSON сериализуется в формате DD.MM.YYYY
+type BirthDate struct {
+	t time.Time
+}
+
+// NewBirthDate создает дату рождения из дня, месяца и года с проверкой календаря
+func NewBirthDate(day, month, year int) (BirthDate, error) {
+	value := fmt.Sprintf("%02d.%02d.%04d", day, month, year)
+	t, err := calendarDate(value, day, month, year)
+	if err != nil {
+		return BirthDate{}, err
+	}
+	return checkBirthRange(value, t)
+}
+
+// ParseBirthDate разбирает дату рождения в форматах:
+// 1987-06-22, 22.06.1987, 22/06/1987, 22 июня 1987, 22 June 1987, June 22, 1987
+func ParseBirthDate(value string) (BirthDate, error) {
+	t, err := parseDate(value)
+	if err != nil {
+		return BirthDate{}, err
+	}
+	return checkBirthRange(strings.TrimSpace(value), t)
+}
+
+// Day возвращает день месяца
+func (d BirthDate) Day() int { return d.t.Day() }
+
+// Month возвращает номер месяца (1-12)
+func (d BirthDate) Month() int { return int(d.t.Month()) }
+
+// Year возвращает год
+func (d BirthDate) Year() int { return d.t.Year() }
+
+// Time возвращает дату как time.Time (полночь UTC)
+func (d BirthDate) Time() time.Time { return d.t }
+
+// IsZero сообщает, что дата не задана
+func (d BirthDate) IsZero() bool { return d.t.IsZero() }
+
+// String возвращает дату в формате DD.MM.YYYY
+func (d BirthDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.t.Format(birthDateLayout)
+}
+
+// MarshalJSON сериализует дату в формате DD.MM.YYYY
+func (d BirthDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON разбирает дату в любом поддерживаемом формате.
+// Пустая строка и null оставляют дату незаданной - это проверяет тег required
+func (d *BirthDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = BirthDate{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &DateError{Value: string(data), Reason: "must be a string"}
+	}
+	if strings.TrimSpace(value) == "" {
+		*d = BirthDate{}
+		return nil
+	}
+
+	date, err := ParseBirthDate(value)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// latestUTCOffset - смещение самого восточного часового пояса (UTC+14)
+const latestUTCOffset = 14 * time.Hour
+
+// checkBirthRange проверяет, что год рождения не раньше минимального, а дата -
+// не позже сегодняшней. Сегодняшняя дата берется по самому восточному часовому
+// поясу, а не по часовому поясу сервера: новый день уже наступил хотя бы для
+// части пользователей
+func checkBirthRange(value string, t time.Time) (BirthDate, error) {
+	if t.Year() < MinBirthYear() {
+		return BirthDate{}, &DateError{Value: value, Reason: fmt.Sprintf("year must not be before %d", MinBirthYear())}
+	}
+	latest := time.Now().UTC().Add(latestUTCOffset)
+	today := time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	if t.After(today) {
+		return BirthDate{}, &DateError{Value: value, Reason: "date must not be in the future"}
+	}
+	return BirthDate{t: t}, nil
+}
+
+// ParseDate разбирает календарную дату в тех же форматах, что и ParseBirthDate,
+// но без ограничений на год (используется для дат прогнозов и линии возраста)
+func ParseDate(value string) (time.Time, error) {
+	return parseDate(value)
+}
+
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrEmptyBirthDate
+	}
+
+	// Числовые форматы: YYYY-MM-DD, DD.MM.YYYY, DD/MM/YYYY
+	for _, sep := range []string{"-", ".", "/"} {
+		parts := strings.Split(value, sep)
+		if len(parts) != 3 {
+			continue
+		}
+
+		nums := make([]int, 3)
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || part == "" || len(part) > 4 {
+				return time.Time{}, &DateError{Value: value, Reason: "expected YYYY-MM-DD, DD.MM.YYYY or DD/MM/YYYY"}
+			}
+			nums[i] = n
+		}
+
+		if sep == "-" {
+			if len(parts[0]) != 4 {
+				return time.Time{}, &DateError{Value: value, Reason: "ISO dates must be YYYY-MM-DD"}
+			}
+			return calendarDate(value, nums[2], nums[1], nums[0])
+		}
+		if len(parts[2]) != 4 {
+			return time.Time{}, &DateError{Value: value, Reason: "year must have 4 digits"}
+		}
+		return calendarDate(value, nums[0], nums[1], nums[2])
+	}
+
+	return parseTextDate(value)
+}
+
+// parseTextDate разбирает дату с названием месяца: "22 июня 1987", "22 June 1987", "June 22, 1987"
+func parseTextDate(value string) (time.Time, error) {
+	fields := strings.Fields(strings.NewReplacer(",", " ").Replace(strings.ToLower(value)))
+
+	day, month, year := 0, 0, 0
+	for _, field := range fields {
+		field = strings.TrimSuffix(field, ".")
+		if field == "г" || field == "год" || field == "года" {
+			continue
+		}
+
+		if m, ok := monthNames[field]; ok {
+			if month != 0 {
+				return time.Time{}, &DateError{Value: value, Reason: "month is specified twice"}
+			}
+			month = m
+			continue
+		}
+
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return time.Time{}, &DateError{Value: value, Reason: fmt.Sprintf("unknown word %q", field)}
+		}
+		switch {
+		case len(field) == 4 && year == 0:
+			year = n
+		case len(field) <= 2 && day == 0:
+			day = n
+		default:
+			return time.Time{}, &DateError{Value: value, Reason: "expected day, month name and 4-digit year"}
+		}
+	}
+
+	if day == 0 || month == 0 || year == 0 {
+		return time.Time{}, &DateError{Value: value, Reason: "expected day, month name and 4-digit year"}
+	}
+	return calendarDate(value, day, month, year)
+}
+
+// calendarDate собирает дату и проверяет, что она существует в календаре (31.02 и 29.02 невисокосного года отклоняются)
+func calendarDate(value string, day, month, year int) (time.Time, error) {
+	if month < 1 || month > 12 {
+		return time.Time{}, &DateError{Value: value, Reason: "month must be between 1 and 12"}
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if day < 1 || t.Day() != day || int(t.Month()) != month {
+		return time.Time{}, &DateError{Value: value, Reason: fmt.Sprintf("day %d does not exist in month %d of %d", day, month, year)}
+	}
+	return t, nil
+}
+
+// monthNames сопоставляет названия месяцев (русские в именительном и родительном падеже, английские) с номером
+var monthNames = map[string]int{
+	"январь": 1, "января": 1, "янв": 1, "january": 1, "jan": 1,
+	"февраль": 2, "февраля": 2, "фев": 2, "february": 2, "feb": 2,
+	"март": 3, "марта": 3, "мар": 3, "march": 3, "mar": 3,
+	"апрель": 4, "апреля": 4, "апр": 4, "april": 4, "apr": 4,
+	"май": 5, "мая": 5, "may": 5,
+	"июнь": 6, "июня": 6, "июн": 6, "june": 6, "jun": 6,
+	"июль": 7, "июля": 7, "июл": 7, "july": 7, "jul": 7,
+	"август": 8, "августа": 8, "авг": 8, "august": 8, "aug": 8,
+	"сентябрь": 9, "сентября": 9, "сен": 9, "сент": 9, "september": 9, "sep": 9, "sept": 9,
+	"октябрь": 10, "октября": 10, "окт": 10, "october": 10, "oct": 10,
+	"ноябрь": 11, "ноября": 11, "ноя": 11, "november": 11, "nov": 11,
+	"декабрь": 12, "декабря": 12, "дек": 12, "december": 12, "dec": 12,
+}
@@ -0,0 +1,118 @@
+package calculator
+
+import (
+	"fmt"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// ChildRoleResult представляет результат расчета роли ребенка в роду
+type ChildRoleResult struct {
+	ChildMatrix              MatrixFate           `json:"childMatrix"`
+	Roles                    ChildRoles           `json:"roles"`
+	CompatibilityWithParents ParentsCompatibility `json:"compatibilityWithParents"`
+	SiblingArcana            *int                 `json:"siblingArcana,omitempty"`
+}
+
+// ChildRoles представляет кармические роли ребенка
+type ChildRoles struct {
+	IsCleanser     bool      `json:"isCleanser"`
+	IsHealer       bool      `json:"isHealer"`
+	IsKarmicMirror bool      `json:"isKarmicMirror"`
+	PrimaryRole    i18n.Text `json:"primaryRole"`
+}
+
+// ParentsCompatibility представляет задачи ребенка с каждым из родителей
+type ParentsCompatibility struct {
+	Parent1 ParentChildCompatibility `json:"parent1"`
+	Parent2 ParentChildCompatibility `json:"parent2"`
+}
+
+// ParentChildCompatibility представляет кармическую задачу ребенка с родителем
+type ParentChildCompatibility struct {
+	TaskArcana         int       `json:"taskArcana"`
+	ArcanaName         i18n.Text `json:"arcanaName"`
+	Interpretation     i18n.Text `json:"interpretation"`
+	ConnectionStrength int       `json:"connectionStrength"`
+}
+
+// CalculateChildRole рассчитывает роль ребенка в роду и его задачи с родителями.
+// siblingDate может быть nil, если брата или сестры нет
+func CalculateChildRole(childDate, parent1Date, parent2Date BirthDate, siblingDate *BirthDate) (*ChildRoleResult, error) {
+	return CalculateChildRoleWithTrace(childDate, parent1Date, parent2Date, siblingDate, nil)
+}
+
+// CalculateChildRoleWithTrace рассчитывает роль ребенка и записывает в trace
+// матрицы членов семьи, выбор ролей и задачи с родителями
+func CalculateChildRoleWithTrace(childDate, parent1Date, parent2Date BirthDate, siblingDate *BirthDate, trace *Trace) (*ChildRoleResult, error) {
+	child, err := CalculateMatrixFateWithTrace(childDate, trace.Scope("child"))
+	if err != nil {
+		return nil, fmt.Errorf("child: %w", err)
+	}
+
+	parent1, err := CalculateMatrixFateWithTrace(parent1Date, trace.Scope("parent1"))
+	if err != nil {
+		return nil, fmt.Errorf("parent 1: %w", err)
+	}
+
+	parent2, err := CalculateMatrixFateWithTrace(parent2Date, trace.Scope("parent2"))
+	if err != nil {
+		return nil, fmt.Errorf("parent 2: %w", err)
+	}
+
+	// 1-4. Роли ребенка в роду вычисляются правилами
+	facts := rules.Facts{}
+	setMatrixFacts(facts, "child", child)
+	setMatrixFacts(facts, "parent1", parent1)
+	setMatrixFacts(facts, "parent2", parent2)
+	roles := evaluateRules(RulesChildRole, facts, nil)
+	for _, role := range []string{"cleanser", "healer", "karmicMirror", "primaryRole"} {
+		trace.rule("roles."+role, RulesChildRole, role, roles)
+	}
+
+	// 5-6. Задачи с родителями
+	taskWithParent1 := trace.reduceSum("parent1.taskArcana", plus("parent1.main", parent1.Main), plus("child.main", child.Main))
+	taskWithParent2 := trace.reduceSum("parent2.taskArcana", plus("parent2.main", parent2.Main), plus("child.main", child.Main))
+
+	result := &ChildRoleResult{
+		ChildMatrix: *child,
+		Roles: ChildRoles{
+			IsCleanser:     roles.Bool("cleanser"),
+			IsHealer:       roles.Bool("healer"),
+			IsKarmicMirror: roles.Bool("karmicMirror"),
+			PrimaryRole:    i18n.T("child.role." + roles.Label("primaryRole")),
+		},
+		CompatibilityWithParents: ParentsCompatibility{
+			Parent1: analyzeParentChildTask(taskWithParent1, trace.Scope("parent1")),
+			Parent2: analyzeParentChildTask(taskWithParent2, trace.Scope("parent2")),
+		},
+	}
+
+	// 7. Задача между детьми (если есть брат или сестра)
+	if siblingDate != nil {
+		sibling, err := CalculateMatrixFateWithTrace(*siblingDate, trace.Scope("sibling"))
+		if err != nil {
+			return nil, fmt.Errorf("sibling: %w", err)
+		}
+		siblingArcana := trace.reduceSum("siblingArcana", plus("child.main", child.Main), plus("sibling.main", sibling.Main))
+		result.SiblingArcana = &siblingArcana
+	}
+
+	return result, nil
+}
+
+// analyzeParentChildTask интерпретирует кармическую задачу ребенка с родителем
+func analyzeParentChildTask(number int, trace *Trace) ParentChildCompatibility {
+	facts := rules.Facts{}
+	facts.Set("taskArcana", number)
+	evaluated := evaluateRules(RulesParentChild, facts, nil)
+	trace.rule("connectionStrength", RulesParentChild, "connectionStrength", evaluated)
+
+	return ParentChildCompatibility{
+		TaskArcana:         number,
+		ArcanaName:         GetArcanaName(number),
+		Interpretation:     i18n.ArcanaChild(number),
+		ConnectionStrength: evaluated.Int("connectionStrength"),
+	}
+}
@@ -1,20 +1,63 @@
 package calculator
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// Профили оценки совместимости
+const (
+	CompatibilityProfileRomantic   = "romantic"
+	CompatibilityProfileBusiness   = "business"
+	CompatibilityProfileFriendship = "friendship"
 )
 
+// ErrUnknownCompatibilityProfile возвращается для профиля оценки не из CompatibilityProfiles
+var ErrUnknownCompatibilityProfile = errors.New("unknown compatibility profile")
+
+// compatibilityProfiles задает набор правил каждого профиля: веса аспектов,
+// благоприятные кармические задачи, бонус и тексты
+var compatibilityProfiles = map[string]string{
+	CompatibilityProfileRomantic:   RulesCompatibility,
+	CompatibilityProfileBusiness:   RulesCompatibilityBusiness,
+	CompatibilityProfileFriendship: RulesCompatibilityFriendship,
+}
+
+// CompatibilityProfiles возвращает имена профилей совместимости по алфавиту
+func CompatibilityProfiles() []string {
+	profiles := make([]string, 0, len(compatibilityProfiles))
+	for profile := range compatibilityProfiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
 // CompatibilityResult представляет результат расчета совместимости
 type CompatibilityResult struct {
-	OverallScore    int              `json:"overallScore"`
-	KarmicTask      int              `json:"karmicTask"`
-	DestinyArcana   int              `json:"destinyArcana"`
-	Aspects         Aspects          `json:"aspects"`
-	Strengths       []string         `json:"strengths"`
-	Challenges      []string         `json:"challenges"`
-	Recommendations []string         `json:"recommendations"`
-	ArcanaNames     ArcanaNames      `json:"arcanaNames"`
+	Profile         string      `json:"profile"`
+	OverallScore    int         `json:"overallScore"`
+	KarmicTask      int         `json:"karmicTask"`
+	DestinyArcana   int         `json:"destinyArcana"`
+	Aspects         Aspects     `json:"aspects"`
+	AspectNames     AspectNames `json:"aspectNames"`
+	Strengths       []i18n.Text `json:"strengths"`
+	Challenges      []i18n.Text `json:"challenges"`
+	Recommendations []i18n.Text `json:"recommendations"`
+	ArcanaNames     ArcanaNames `json:"arcanaNames"`
+}
+
+// CompatibilityReport - полный результат /calculate/compatibility: совместимость и матрица пары
+type CompatibilityReport struct {
+	CompatibilityResult
+	CoupleMatrix *FullMatrix `json:"coupleMatrix"`
 }
 
 // Aspects представляет аспекты совместимости
@@ -25,132 +68,191 @@ type Aspects struct {
 	Karmic    int `json:"karmic"`
 }
 
+// AspectNames представляет названия аспектов в терминах профиля
+type AspectNames struct {
+	Spiritual i18n.Text `json:"spiritual"`
+	Emotional i18n.Text `json:"emotional"`
+	Material  i18n.Text `json:"material"`
+	Karmic    i18n.Text `json:"karmic"`
+}
+
 // ArcanaNames представляет названия арканов
 type ArcanaNames struct {
-	KarmicTask    string `json:"karmicTask"`
-	DestinyArcana string `json:"destinyArcana"`
+	KarmicTask    i18n.Text `json:"karmicTask"`
+	DestinyArcana i18n.Text `json:"destinyArcana"`
 }
 
-// CalculateCompatibility рассчитывает совместимость двух людей
-func CalculateCompatibility(person1, person2 *MatrixFate) (*CompatibilityResult, error) {
-	if person1 == nil || person2 == nil {
-		return nil, fmt.Errorf("both persons must be provided")
-	}
-
-	// 1. Кармическая задача пары
-	karmicTask := reduceTo22(person1.Main + person2.Main)
+// CalculateCompatibilityReport рассчитывает совместимость по профилю оценки и матрицу пары
+// по датам рождения. Ошибки даты рождения оборачиваются в PersonDateError с номером человека
+func CalculateCompatibilityReport(birthDate1, birthDate2 BirthDate, profile string) (*CompatibilityReport, error) {
+	return CalculateCompatibilityReportWithTrace(birthDate1, birthDate2, profile, nil)
+}
 
-	// 2. Аркан предназначения пары
-	destinyArcana := reduceTo22(person1.Spiritual + person2.Spiritual)
-
-	// 3. Духовная совместимость (близость духовных путей)
-	spiritualDiff := int(math.Abs(float64(person1.Spiritual - person2.Spiritual)))
-	var spiritual int
-	switch {
-	case spiritualDiff <= 3:
-		spiritual = 90
-	case spiritualDiff <= 7:
-		spiritual = 70
-	default:
-		spiritual = 50
+// CalculateCompatibilityReportWithTrace рассчитывает совместимость с матрицей пары и записывает шаги в trace
+func CalculateCompatibilityReportWithTrace(birthDate1, birthDate2 BirthDate, profile string, trace *Trace) (*CompatibilityReport, error) {
+	person1, err := CalculateMatrixFateWithTrace(birthDate1, trace.Scope("person1"))
+	if err != nil {
+		return nil, &PersonDateError{Person: 1, Err: err}
 	}
-
-	// 4. Эмоциональная совместимость (основные арканы)
-	var emotional int
-	if person1.Main == person2.Main {
-		emotional = 100
-	} else if math.Abs(float64(person1.Main-person2.Main)) <= 5 {
-		emotional = 80
-	} else {
-		emotional = 60
+	person2, err := CalculateMatrixFateWithTrace(birthDate2, trace.Scope("person2"))
+	if err != nil {
+		return nil, &PersonDateError{Person: 2, Err: err}
 	}
 
-	// 5. Материальная совместимость (социальные арканы)
-	var material int
-	if person1.Social == person2.Social {
-		material = 100
-	} else if math.Abs(float64(person1.Social-person2.Social)) <= 4 {
-		material = 75
-	} else {
-		material = 55
+	result, err := CalculateCompatibilityWithTrace(person1, person2, profile, trace)
+	if err != nil {
+		return nil, err
 	}
 
-	// 6. Кармическая связь (хвосты отражают друг друга)
-	var karmic int
-	if person1.Tail == person2.Main || person2.Tail == person1.Main {
-		karmic = 95
-	} else if person1.Tail == person2.Spiritual || person2.Tail == person1.Spiritual {
-		karmic = 85
-	} else {
-		karmic = 60
+	coupleMatrix, err := CalculateCoupleMatrixWithTrace(birthDate1, birthDate2, trace.Scope("coupleMatrix"))
+	if err != nil {
+		return nil, err
 	}
 
-	// 7. Бонусы за благоприятные кармические задачи
-	favorableTasks := []int{7, 11, 17, 19, 20}
-	taskBonus := 5
-	for _, task := range favorableTasks {
-		if karmicTask == task {
-			taskBonus = 15
-			break
-		}
-	}
+	return &CompatibilityReport{
+		CompatibilityResult: *result,
+		CoupleMatrix:        coupleMatrix,
+	}, nil
+}
 
-	// 8. Общая совместимость
-	baseScore := (spiritual + emotional + material + karmic) / 4
-	overallScore := int(math.Min(100, float64(baseScore+taskBonus)))
+// PersonDateError - ошибка даты рождения одного из участников расчета (нумерация с 1)
+type PersonDateError struct {
+	Person int
+	Err    error
+}
 
-	// 9. Определение сильных сторон
-	strengths := make([]string, 0)
-	if spiritual >= 80 {
-		strengths = append(strengths, "Глубокое духовное понимание")
-	}
-	if emotional >= 80 {
-		strengths = append(strengths, "Эмоциональное единство")
+func (e *PersonDateError) Error() string {
+	return fmt.Sprintf("person %d: %v", e.Person, e.Err)
+}
+
+func (e *PersonDateError) Unwrap() error {
+	return e.Err
+}
+
+// CalculateCompatibility рассчитывает совместимость двух людей по профилю
+// оценки (пустой профиль - романтический)
+func CalculateCompatibility(person1, person2 *MatrixFate, profile string) (*CompatibilityResult, error) {
+	return CalculateCompatibilityWithTrace(person1, person2, profile, nil)
+}
+
+// CalculateCompatibilityWithTrace рассчитывает совместимость и записывает
+// в trace арканы пары, выбор каждого аспекта и итоговую оценку
+func CalculateCompatibilityWithTrace(person1, person2 *MatrixFate, profile string, trace *Trace) (*CompatibilityResult, error) {
+	if person1 == nil || person2 == nil {
+		return nil, fmt.Errorf("both persons must be provided")
 	}
-	if karmic >= 85 {
-		strengths = append(strengths, "Сильная кармическая связь")
+
+	if profile == "" {
+		profile = CompatibilityProfileRomantic
 	}
-	for _, task := range favorableTasks {
-		if karmicTask == task {
-			strengths = append(strengths, fmt.Sprintf("Благоприятная кармическая задача: %s", GetArcanaName(karmicTask)))
-			break
-		}
+	ruleSet, ok := compatibilityProfiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompatibilityProfile, profile)
 	}
 
-	// 10. Определение вызовов
-	challenges := make([]string, 0)
-	if spiritualDiff > 10 {
-		challenges = append(challenges, "Разные духовные пути требуют взаимного уважения")
+	// 1. Кармическая задача пары
+	karmicTask := trace.reduceSum("karmicTask", plus("person1.main", person1.Main), plus("person2.main", person2.Main))
+
+	// 2. Аркан предназначения пары
+	destinyArcana := trace.reduceSum("destinyArcana", plus("person1.spiritual", person1.Spiritual), plus("person2.spiritual", person2.Spiritual))
+
+	// 3. Аспекты, веса, бонус и тексты вычисляются правилами профиля
+	facts := rules.Facts{}
+	facts.Set("karmicTask", karmicTask)
+	facts.Set("destinyArcana", destinyArcana)
+	facts.Set("spiritualDiff", trace.absDiff("spiritualDiff", plus("person1.spiritual", person1.Spiritual), plus("person2.spiritual", person2.Spiritual)))
+	facts.Set("mainDiff", trace.absDiff("mainDiff", plus("person1.main", person1.Main), plus("person2.main", person2.Main)))
+	facts.Set("socialDiff", trace.absDiff("socialDiff", plus("person1.social", person1.Social), plus("person2.social", person2.Social)))
+	setMatrixFacts(facts, "person1", person1)
+	setMatrixFacts(facts, "person2", person2)
+	evaluated := evaluateRules(ruleSet, facts, nil)
+
+	aspects := []string{"spiritual", "emotional", "material", "karmic"}
+	for _, aspect := range aspects {
+		trace.rule("aspects."+aspect, ruleSet, aspect, evaluated)
+	}
+	for _, aspect := range aspects {
+		trace.rule("weights."+aspect, ruleSet, aspect+"Weight", evaluated)
 	}
-	if math.Abs(float64(person1.Main-person2.Main)) > 10 {
-		challenges = append(challenges, "Разные жизненные приоритеты - источник роста")
+	trace.rule("favorableTask", ruleSet, "favorableTask", evaluated)
+	trace.rule("taskBonus", ruleSet, "taskBonus", evaluated)
+
+	// 4. Общая совместимость - взвешенное среднее аспектов с бонусом за кармическую задачу
+	var weighted, weights int
+	operands := make([]TraceOperand, 0, 2*len(aspects))
+	products := make([]string, 0, len(aspects))
+	weightTerms := make([]string, 0, len(aspects))
+	for _, aspect := range aspects {
+		score, weight := evaluated.Int(aspect), evaluated.Int(aspect+"Weight")
+		if weight < 0 {
+			return nil, fmt.Errorf("compatibility profile %s: weight of %s must not be negative", profile, aspect)
+		}
+		weighted += score * weight
+		weights += weight
+		operands = append(operands, plus(aspect, score), plus(aspect+"Weight", weight))
+		products = append(products, fmt.Sprintf("%d * %d", score, weight))
+		weightTerms = append(weightTerms, strconv.Itoa(weight))
 	}
-	if karmicTask == 16 {
-		challenges = append(challenges, "Кармическая задача Башня требует совместного преодоления кризисов")
+	if weights == 0 {
+		return nil, fmt.Errorf("compatibility profile %s: aspect weights must not all be zero", profile)
 	}
 
-	// 11. Рекомендации
-	recommendations := []string{
-		fmt.Sprintf("Работайте вместе над задачей: %s", GetArcanaName(karmicTask)),
-		fmt.Sprintf("Ваше общее предназначение связано с энергией: %s", GetArcanaName(destinyArcana)),
+	baseScore := weighted / weights
+	taskBonus := evaluated.Int("taskBonus")
+	overallScore := int(math.Min(100, float64(baseScore+taskBonus)))
+	if trace != nil {
+		sign, bonus := "+", taskBonus
+		if bonus < 0 {
+			sign, bonus = "-", -bonus
+		}
+		trace.record(TraceStep{
+			Name:     "baseScore",
+			Formula:  "(spiritual * spiritualWeight + emotional * emotionalWeight + material * materialWeight + karmic * karmicWeight) / (spiritualWeight + emotionalWeight + materialWeight + karmicWeight)",
+			Operands: operands,
+			Value:    baseScore,
+			Expression: fmt.Sprintf("(%s) / (%s) = %d / %d = %d",
+				strings.Join(products, " + "), strings.Join(weightTerms, " + "), weighted, weights, baseScore),
+		})
+		trace.record(TraceStep{
+			Name:       "overallScore",
+			Formula:    "min(100, baseScore + taskBonus)",
+			Operands:   []TraceOperand{plus("baseScore", baseScore), plus("taskBonus", taskBonus)},
+			Value:      overallScore,
+			Expression: fmt.Sprintf("min(100, %d %s %d) = %d", baseScore, sign, bonus, overallScore),
+		})
 	}
 
 	return &CompatibilityResult{
+		Profile:       profile,
 		OverallScore:  overallScore,
 		KarmicTask:    karmicTask,
 		DestinyArcana: destinyArcana,
 		Aspects: Aspects{
-			Spiritual: spiritual,
-			Emotional: emotional,
-			Material:  material,
-			Karmic:    karmic,
+			Spiritual: evaluated.Int("spiritual"),
+			Emotional: evaluated.Int("emotional"),
+			Material:  evaluated.Int("material"),
+			Karmic:    evaluated.Int("karmic"),
 		},
-		Strengths:       strengths,
-		Challenges:      challenges,
-		Recommendations: recommendations,
+		AspectNames: AspectNames{
+			Spiritual: i18n.T("compatibility.profile." + profile + ".aspect.spiritual"),
+			Emotional: i18n.T("compatibility.profile." + profile + ".aspect.emotional"),
+			Material:  i18n.T("compatibility.profile." + profile + ".aspect.material"),
+			Karmic:    i18n.T("compatibility.profile." + profile + ".aspect.karmic"),
+		},
+		Strengths:       evaluated.Texts("strengths"),
+		Challenges:      evaluated.Texts("challenges"),
+		Recommendations: evaluated.Texts("recommendations"),
 		ArcanaNames: ArcanaNames{
 			KarmicTask:    GetArcanaName(karmicTask),
 			DestinyArcana: GetArcanaName(destinyArcana),
 		},
 	}, nil
 }
+
+// setMatrixFacts добавляет в факты арканы матрицы судьбы с префиксом
+func setMatrixFacts(facts rules.Facts, prefix string, m *MatrixFate) {
+	facts.Set(prefix+".main", m.Main)
+	facts.Set(prefix+".spiritual", m.Spiritual)
+	facts.Set(prefix+".social", m.Social)
+	facts.Set(prefix+".tail", m.Tail)
+}
@@ -0,0 +1,144 @@
+package calculator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"arcanum/internal/i18n"
+)
+
+const (
+	// DefaultTimezone - часовой пояс, если пользователь не указал свой
+	DefaultTimezone = "UTC"
+
+	// DayEnergyNextDays - количество следующих дней в недельной ленте
+	DayEnergyNextDays = 7
+)
+
+// DayEnergy представляет персональный аркан одного дня
+type DayEnergy struct {
+	Date       string    `json:"date"`
+	Arcana     int       `json:"arcana"`
+	ArcanaName i18n.Text `json:"arcanaName"`
+	Advice     i18n.Text `json:"advice"`
+}
+
+// DayEnergyForecast представляет аркан дня и следующие дни для недельной ленты
+type DayEnergyForecast struct {
+	BirthDate BirthDate   `json:"birthDate"`
+	Day       DayEnergy   `json:"day"`
+	NextDays  []DayEnergy `json:"nextDays"`
+}
+
+// DayEnergyReport - ответ расчета энергии дня: прогноз и часовой пояс,
+// в котором определен текущий день
+type DayEnergyReport struct {
+	Timezone string `json:"timezone"`
+	DayEnergyForecast
+}
+
+// LoadTimezone загружает часовой пояс IANA (Europe/Moscow, Asia/Tokyo).
+// Пустое имя означает UTC. Local запрещен: результат не должен зависеть
+// от часового пояса сервера
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown timezone: %s", name)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone: %s", name)
+	}
+	return loc, nil
+}
+
+// Today возвращает текущую календарную дату в часовом поясе timezone
+// (полночь UTC, как у дат рождения)
+func Today(timezone string) (time.Time, error) {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// CalculateDayEnergy рассчитывает персональный аркан дня date и следующих
+// дней. Дата берется как календарная: часовой пояс учитывается при выборе
+// текущей даты через Today
+func CalculateDayEnergy(birthDate BirthDate, date time.Time) (*DayEnergyForecast, error) {
+	return CalculateDayEnergyWithTrace(birthDate, date, nil)
+}
+
+// CalculateDayEnergyWithTrace рассчитывает аркан дня и записывает в trace
+// расчет каждого дня ленты
+func CalculateDayEnergyWithTrace(birthDate BirthDate, date time.Time, trace *Trace) (*DayEnergyForecast, error) {
+	if birthDate.IsZero() {
+		return nil, ErrEmptyBirthDate
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Before(birthDate.Time()) {
+		return nil, fmt.Errorf("date must not be before birth date")
+	}
+
+	result := &DayEnergyForecast{
+		BirthDate: birthDate,
+		Day:       dayEnergy(trace.Scope("day"), birthDate, date),
+		NextDays:  make([]DayEnergy, 0, DayEnergyNextDays),
+	}
+	for i := 1; i <= DayEnergyNextDays; i++ {
+		scope := trace.Scope(fmt.Sprintf("nextDays[%d]", i-1))
+		result.NextDays = append(result.NextDays, dayEnergy(scope, birthDate, date.AddDate(0, 0, i)))
+	}
+
+	return result, nil
+}
+
+// CalculateDayEnergyReport рассчитывает энергию дня date для ответа API.
+// Часовой пояс проверяется и при явной дате, чтобы ответ был предсказуемым
+func CalculateDayEnergyReport(birthDate BirthDate, date time.Time, timezone string) (*DayEnergyReport, error) {
+	return CalculateDayEnergyReportWithTrace(birthDate, date, timezone, nil)
+}
+
+// CalculateDayEnergyReportWithTrace рассчитывает ответ энергии дня
+// и записывает в trace расчет каждого дня ленты
+func CalculateDayEnergyReportWithTrace(birthDate BirthDate, date time.Time, timezone string, trace *Trace) (*DayEnergyReport, error) {
+	if _, err := LoadTimezone(timezone); err != nil {
+		return nil, err
+	}
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	forecast, err := CalculateDayEnergyWithTrace(birthDate, date, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DayEnergyReport{
+		Timezone:          timezone,
+		DayEnergyForecast: *forecast,
+	}, nil
+}
+
+// dayEnergy рассчитывает аркан дня: аркан личного месяца (как в прогнозе)
+// плюс число месяца
+func dayEnergy(trace *Trace, birthDate BirthDate, date time.Time) DayEnergy {
+	personalYear := personalYearArcana(trace, birthDate.Day(), birthDate.Month(), date.Year())
+	personalMonth := trace.reduceSum("personalMonth",
+		plus("personalYear", personalYear), plus("date.month", int(date.Month())))
+	arcana := trace.reduceSum("arcana", plus("personalMonth", personalMonth), plus("date.day", date.Day()))
+
+	return DayEnergy{
+		Date:       date.Format(birthDateLayout),
+		Arcana:     arcana,
+		ArcanaName: GetArcanaName(arcana),
+		Advice:     i18n.T("day.advice." + strconv.Itoa(arcana)),
+	}
+}
@@ -0,0 +1,272 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// Типы событий для подбора благоприятных дат
+const (
+	FavorableEventWedding  = "wedding"
+	FavorableEventLaunch   = "launch"
+	FavorableEventContract = "contract"
+)
+
+const (
+	// MaxFavorableDatesRange - максимальная длина диапазона поиска в днях
+	MaxFavorableDatesRange = 366
+	// DefaultFavorableDatesLimit - количество дат в ответе по умолчанию
+	DefaultFavorableDatesLimit = 10
+	// MaxFavorableDatesLimit - максимальное количество дат в ответе
+	MaxFavorableDatesLimit = 31
+	// MaxFavorableDatesParticipants - максимальное количество участников события
+	MaxFavorableDatesParticipants = 2
+)
+
+// favorableEvents задает набор правил каждого типа события: оценки арканов,
+// веса, исключаемые даты и пояснения
+var favorableEvents = map[string]string{
+	FavorableEventWedding:  RulesFavorableWedding,
+	FavorableEventLaunch:   RulesFavorableLaunch,
+	FavorableEventContract: RulesFavorableContract,
+}
+
+// FavorableEvents возвращает типы событий по алфавиту
+func FavorableEvents() []string {
+	events := make([]string, 0, len(favorableEvents))
+	for event := range favorableEvents {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// FavorableDatesResult представляет результат подбора благоприятных дат
+type FavorableDatesResult struct {
+	Event     string          `json:"event"`
+	EventName i18n.Text       `json:"eventName"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Checked   int             `json:"checked"`
+	Excluded  int             `json:"excluded"`
+	Dates     []FavorableDate `json:"dates"`
+}
+
+// FavorableDate представляет оцененную дату
+type FavorableDate struct {
+	Date         string       `json:"date"`
+	Score        int          `json:"score"`
+	DayArcana    DateArcana   `json:"dayArcana"`
+	Participants []DateArcana `json:"participants"`
+	PairArcana   *DateArcana  `json:"pairArcana,omitempty"`
+	Reasons      []i18n.Text  `json:"reasons"`
+}
+
+// DateArcana представляет аркан даты с названием
+type DateArcana struct {
+	Arcana     int       `json:"arcana"`
+	ArcanaName i18n.Text `json:"arcanaName"`
+}
+
+// CalculateFavorableDates оценивает каждый день диапазона from-to для
+// события и возвращает limit лучших дат. Участники - один или два человека
+func CalculateFavorableDates(event string, participants []GroupMember, from, to time.Time, limit int) (*FavorableDatesResult, error) {
+	return CalculateFavorableDatesWithTrace(event, participants, from, to, limit, nil)
+}
+
+// CalculateFavorableDatesWithTrace подбирает даты и записывает в trace
+// расчет каждой даты, попавшей в ответ
+func CalculateFavorableDatesWithTrace(event string, participants []GroupMember, from, to time.Time, limit int, trace *Trace) (*FavorableDatesResult, error) {
+	ruleSet, ok := favorableEvents[event]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type: %s", event)
+	}
+
+	if len(participants) < 1 || len(participants) > MaxFavorableDatesParticipants {
+		return nil, fmt.Errorf("event must have from 1 to %d participants", MaxFavorableDatesParticipants)
+	}
+	for i, participant := range participants {
+		if participant.Matrix == nil {
+			return nil, fmt.Errorf("participant %d must be provided", i+1)
+		}
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date must not be before start date")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > MaxFavorableDatesRange {
+		return nil, fmt.Errorf("date range must not exceed %d days", MaxFavorableDatesRange)
+	}
+
+	if limit == 0 {
+		limit = DefaultFavorableDatesLimit
+	}
+	if limit < 1 || limit > MaxFavorableDatesLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxFavorableDatesLimit)
+	}
+
+	names := i18n.Params{}
+	for i, participant := range participants {
+		name := i18n.Raw(participant.Name)
+		if participant.Name == "" {
+			name = i18n.T("group.member", i18n.Params{"number": i + 1})
+		}
+		names[fmt.Sprintf("person%dName", i+1)] = name
+	}
+
+	// 1. Оценка каждого дня диапазона без записи шагов
+	dates := make([]FavorableDate, 0, days)
+	excluded := 0
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		candidate, ok, err := favorableDate(nil, ruleSet, event, participants, names, date)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			excluded++
+			continue
+		}
+		dates = append(dates, candidate)
+	}
+
+	// 2. Лучшие даты: по убыванию оценки, при равенстве - более ранние
+	sort.SliceStable(dates, func(i, j int) bool {
+		return dates[i].Score > dates[j].Score
+	})
+	if len(dates) > limit {
+		dates = dates[:limit]
+	}
+
+	// 3. Объяснение расчета только для дат из ответа
+	if trace != nil {
+		for i := range dates {
+			date, _ := time.Parse(birthDateLayout, dates[i].Date)
+			if _, _, err := favorableDate(trace.Scope(fmt.Sprintf("dates[%d]", i)), ruleSet, event, participants, names, date); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &FavorableDatesResult{
+		Event:     event,
+		EventName: i18n.T("favorable.event." + event),
+		From:      from.Format(birthDateLayout),
+		To:        to.Format(birthDateLayout),
+		Checked:   days,
+		Excluded:  excluded,
+		Dates:     dates,
+	}, nil
+}
+
+// favorableDate оценивает один день. Возвращает false, если правила события
+// исключают дату (например, аркан пары 16 Башня)
+func favorableDate(trace *Trace, ruleSet, event string, participants []GroupMember, names i18n.Params, date time.Time) (FavorableDate, bool, error) {
+	// 1. Аркан дня - сумма цифр даты, как основной аркан матрицы
+	total := trace.digitSum("total", "date", extractDigits(date.Format(birthDateLayout)))
+	dayArcana := trace.reduceSum("dayArcana", plus("total", total))
+
+	facts := rules.Facts{}
+	facts.Set("dayArcana", dayArcana)
+	facts.Set("participants", len(participants))
+
+	// 2. Аркан дня для каждого участника и для пары
+	arcana := make([]DateArcana, 0, len(participants))
+	mains := make([]TraceOperand, 0, len(participants)+1)
+	for i := 1; i <= MaxFavorableDatesParticipants; i++ {
+		prefix := fmt.Sprintf("person%d", i)
+		if i > len(participants) {
+			facts.Set(prefix+".arcana", 0)
+			setMatrixFacts(facts, prefix, &MatrixFate{})
+			continue
+		}
+
+		matrix := participants[i-1].Matrix
+		personArcana := trace.reduceSum(prefix+".arcana", plus(prefix+".main", matrix.Main), plus("dayArcana", dayArcana))
+		facts.Set(prefix+".arcana", personArcana)
+		setMatrixFacts(facts, prefix, matrix)
+		arcana = append(arcana, DateArcana{Arcana: personArcana, ArcanaName: GetArcanaName(personArcana)})
+		mains = append(mains, plus(prefix+".main", matrix.Main))
+	}
+
+	var pair *DateArcana
+	pairArcana := 0
+	if len(participants) == 2 {
+		pairArcana = trace.reduceSum("pairArcana", append(mains, plus("dayArcana", dayArcana))...)
+		pair = &DateArcana{Arcana: pairArcana, ArcanaName: GetArcanaName(pairArcana)}
+	}
+	facts.Set("pairArcana", pairArcana)
+
+	// 3. Оценки, веса и исключение даты задаются правилами события
+	evaluated := evaluateRules(ruleSet, facts, names)
+	trace.rule("excluded", ruleSet, "excluded", evaluated)
+	if evaluated.Bool("excluded") {
+		return FavorableDate{}, false, nil
+	}
+
+	aspects := []string{"day", "person1"}
+	if len(participants) == 2 {
+		aspects = append(aspects, "person2", "pair")
+	}
+	for _, aspect := range aspects {
+		trace.rule(aspect+"Score", ruleSet, aspect+"Score", evaluated)
+	}
+	for _, aspect := range aspects {
+		trace.rule(aspect+"Weight", ruleSet, aspect+"Weight", evaluated)
+	}
+
+	// 4. Итоговая оценка - взвешенное среднее оценок дня, участников и пары
+	var weighted, weights int
+	operands := make([]TraceOperand, 0, 2*len(aspects))
+	products := make([]string, 0, len(aspects))
+	weightTerms := make([]string, 0, len(aspects))
+	for _, aspect := range aspects {
+		score, weight := evaluated.Int(aspect+"Score"), evaluated.Int(aspect+"Weight")
+		if weight < 0 {
+			return FavorableDate{}, false, fmt.Errorf("event %s: weight of %s must not be negative", event, aspect)
+		}
+		weighted += score * weight
+		weights += weight
+		operands = append(operands, plus(aspect+"Score", score), plus(aspect+"Weight", weight))
+		products = append(products, fmt.Sprintf("%d * %d", score, weight))
+		weightTerms = append(weightTerms, strconv.Itoa(weight))
+	}
+	if weights == 0 {
+		return FavorableDate{}, false, fmt.Errorf("event %s: weights must not all be zero", event)
+	}
+
+	score := weighted / weights
+	if trace != nil {
+		formula := make([]string, 0, len(aspects))
+		weightNames := make([]string, 0, len(aspects))
+		for _, aspect := range aspects {
+			formula = append(formula, aspect+"Score * "+aspect+"Weight")
+			weightNames = append(weightNames, aspect+"Weight")
+		}
+		trace.record(TraceStep{
+			Name:     "score",
+			Formula:  fmt.Sprintf("(%s) / (%s)", strings.Join(formula, " + "), strings.Join(weightNames, " + ")),
+			Operands: operands,
+			Value:    score,
+			Expression: fmt.Sprintf("(%s) / (%s) = %d / %d = %d",
+				strings.Join(products, " + "), strings.Join(weightTerms, " + "), weighted, weights, score),
+		})
+	}
+
+	return FavorableDate{
+		Date:         date.Format(birthDateLayout),
+		Score:        score,
+		DayArcana:    DateArcana{Arcana: dayArcana, ArcanaName: GetArcanaName(dayArcana)},
+		Participants: arcana,
+		PairArcana:   pair,
+		Reasons:      evaluated.Texts("reasons"),
+	}, true, nil
+}
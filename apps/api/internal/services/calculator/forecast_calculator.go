@@ -0,0 +1,127 @@
+package calculator
+
+import (
+	"fmt"
+
+	"arcanum/internal/i18n"
+)
+
+// MaxForecastYears - максимальное количество лет в одном прогнозе
+const MaxForecastYears = 10
+
+// Forecast представляет прогноз на год
+type Forecast struct {
+	Year         int             `json:"year"`
+	Age          int             `json:"age"`
+	PersonalYear ForecastArcana  `json:"personalYear"`
+	AgePoint     ForecastArcana  `json:"agePoint"`
+	Months       []MonthForecast `json:"months"`
+}
+
+// ForecastArcana представляет аркан периода с интерпретацией
+type ForecastArcana struct {
+	Arcana         int       `json:"arcana"`
+	ArcanaName     i18n.Text `json:"arcanaName"`
+	Interpretation i18n.Text `json:"interpretation"`
+}
+
+// MonthForecast представляет прогноз на месяц
+type MonthForecast struct {
+	Month int `json:"month"`
+	ForecastArcana
+}
+
+// CalculateForecast рассчитывает прогноз на указанный год
+func CalculateForecast(birthDate BirthDate, year int) (*Forecast, error) {
+	forecasts, err := CalculateForecasts(birthDate, year, year)
+	if err != nil {
+		return nil, err
+	}
+	return &forecasts[0], nil
+}
+
+// CalculateForecasts рассчитывает прогнозы на диапазон лет включительно
+func CalculateForecasts(birthDate BirthDate, fromYear, toYear int) ([]Forecast, error) {
+	return CalculateForecastsWithTrace(birthDate, fromYear, toYear, nil)
+}
+
+// CalculateForecastsWithTrace рассчитывает прогнозы и записывает в trace
+// линию возраста и арканы каждого года с префиксом года
+func CalculateForecastsWithTrace(birthDate BirthDate, fromYear, toYear int, trace *Trace) ([]Forecast, error) {
+	if birthDate.IsZero() {
+		return nil, ErrEmptyBirthDate
+	}
+
+	if toYear < fromYear {
+		return nil, fmt.Errorf("end year must not be before start year")
+	}
+	if toYear-fromYear+1 > MaxForecastYears {
+		return nil, fmt.Errorf("forecast range must not exceed %d years", MaxForecastYears)
+	}
+
+	day, month, birthYear := birthDate.Day(), birthDate.Month(), birthDate.Year()
+	if fromYear < birthYear {
+		return nil, fmt.Errorf("forecast year must not be before birth year")
+	}
+
+	timeline, err := CalculateAgeTimelineWithTrace(birthDate, trace.Scope("timeline"))
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]Forecast, 0, toYear-fromYear+1)
+	for year := fromYear; year <= toYear; year++ {
+		yearTrace := trace.Scope(fmt.Sprint(year))
+
+		personalYear := personalYearArcana(yearTrace, day, month, year)
+
+		// Арканы личных месяцев
+		months := make([]MonthForecast, 0, 12)
+		for m := 1; m <= 12; m++ {
+			arcana := yearTrace.reduceSum(fmt.Sprintf("months[%d]", m),
+				plus("personalYear", personalYear), plus("month", m))
+			months = append(months, MonthForecast{
+				Month:          m,
+				ForecastArcana: newForecastArcana(arcana),
+			})
+		}
+
+		age := year - birthYear
+
+		forecasts = append(forecasts, Forecast{
+			Year:         year,
+			Age:          age,
+			PersonalYear: newForecastArcana(personalYear),
+			AgePoint:     newForecastArcana(agePointArcana(yearTrace, timeline, age)),
+			Months:       months,
+		})
+	}
+
+	return forecasts, nil
+}
+
+// personalYearArcana рассчитывает аркан личного года: день + месяц рождения + сумма цифр года
+func personalYearArcana(trace *Trace, day, month, year int) int {
+	yearDigits := trace.digitSum("yearDigits", "year", extractDigits(fmt.Sprint(year)))
+	return trace.reduceSum("personalYear", plus("day", day), plus("month", month), plus("yearDigits", yearDigits))
+}
+
+// agePointArcana возвращает аркан линии возраста, активный в указанном возрасте.
+// После 80 лет линия возраста начинается заново
+func agePointArcana(trace *Trace, timeline *AgeTimeline, age int) int {
+	segment, err := timeline.At(float64(age % TimelineMaxAge))
+	if err != nil {
+		return 0
+	}
+	index := int(segment.StartAge / timelineSegmentYears)
+	return trace.assign("agePoint", plus(fmt.Sprintf("timeline.segments[%d]", index), segment.Arcana))
+}
+
+// newForecastArcana создает аркан периода с названием и интерпретацией
+func newForecastArcana(number int) ForecastArcana {
+	return ForecastArcana{
+		Arcana:         number,
+		ArcanaName:     GetArcanaName(number),
+		Interpretation: i18n.ArcanaForecast(number),
+	}
+}
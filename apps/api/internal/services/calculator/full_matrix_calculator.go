@@ -0,0 +1,270 @@
+package calculator
+
+import (
+	"fmt"
+
+	"arcanum/internal/i18n"
+)
+
+// Идентификаторы точек полной Матрицы Судьбы (октаграммы)
+const (
+	PointDay         = "A"  // Левый угол - аркан дня
+	PointMonth       = "B"  // Верхний угол - аркан месяца
+	PointYear        = "C"  // Правый угол - аркан года
+	PointBottom      = "D"  // Нижний угол - сумма A+B+C
+	PointCenter      = "E"  // Центр - зона комфорта
+	PointTopLeft     = "F"  // Родовой квадрат: A+B
+	PointTopRight    = "G"  // Родовой квадрат: B+C
+	PointBottomRight = "H"  // Родовой квадрат: C+D
+	PointBottomLeft  = "I"  // Родовой квадрат: D+A
+	PointDayInner    = "A1" // A+E
+	PointDayOuter    = "A2" // A+A1
+	PointMonthInner  = "B1" // B+E
+	PointMonthOuter  = "B2" // B+B1
+	PointYearInner   = "C1" // C+E
+	PointYearOuter   = "C2" // C+C1
+	PointTailInner   = "D1" // D+E
+	PointTailOuter   = "D2" // D+D1
+	PointBalance     = "X"  // Точка равновесия: C1+D1
+	PointLove        = "L"  // Точка отношений: X+D1
+	PointMoney       = "M"  // Точка денег: X+C1
+)
+
+// FullMatrix представляет полную Матрицу Судьбы (октаграмму)
+type FullMatrix struct {
+	Points      []MatrixPoint `json:"points"`
+	ComfortZone int           `json:"comfortZone"`
+	// KarmicTail - точки хвоста от нижней точки к центру: D, D2, D1
+	KarmicTail []int `json:"karmicTail"`
+	// KarmicProgram - программа хвоста. Ее цепочка записывается в обратном
+	// порядке, как у практиков: D1, D2, D (18-9-9)
+	KarmicProgram *KarmicProgram `json:"karmicProgram,omitempty"`
+	LoveChannel   []int          `json:"loveChannel"`
+	MoneyChannel  []int          `json:"moneyChannel"`
+	Purposes      MatrixPurposes `json:"purposes"`
+	BirthDate     *BirthDate     `json:"birthDate,omitempty"`
+}
+
+// MatrixPoint представляет точку матрицы с координатами для отрисовки.
+// Координаты нормированы в квадрат 0..1, центр матрицы - (0.5, 0.5)
+type MatrixPoint struct {
+	ID         string    `json:"id"`
+	Name       i18n.Text `json:"name"`
+	Arcana     int       `json:"arcana"`
+	ArcanaName i18n.Text `json:"arcanaName"`
+	X          float64   `json:"x"`
+	Y          float64   `json:"y"`
+}
+
+// MatrixPurposes представляет предназначения (линии Неба и Земли)
+type MatrixPurposes struct {
+	Sky      int `json:"sky"`
+	Earth    int `json:"earth"`
+	Personal int `json:"personal"`
+}
+
+// Point возвращает точку матрицы по идентификатору
+func (m *FullMatrix) Point(id string) (MatrixPoint, bool) {
+	for _, p := range m.Points {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return MatrixPoint{}, false
+}
+
+// CalculateFullMatrix рассчитывает все точки Матрицы Судьбы по дате рождения
+func CalculateFullMatrix(birthDate BirthDate) (*FullMatrix, error) {
+	return CalculateFullMatrixWithTrace(birthDate, nil)
+}
+
+// CalculateFullMatrixWithTrace рассчитывает все точки Матрицы Судьбы
+// и записывает формулу каждой точки в trace
+func CalculateFullMatrixWithTrace(birthDate BirthDate, trace *Trace) (*FullMatrix, error) {
+	if birthDate.IsZero() {
+		return nil, ErrEmptyBirthDate
+	}
+
+	day, month, year := birthDate.Day(), birthDate.Month(), birthDate.Year()
+
+	// 1. Углы прямого квадрата (личные качества)
+	a := trace.reduceSum(PointDay, plus("day", day))
+	b := trace.reduceSum(PointMonth, plus("month", month))
+	c := trace.reduceSum(PointYear, plus("year", year))
+	d := trace.reduceSum(PointBottom, plus(PointDay, a), plus(PointMonth, b), plus(PointYear, c))
+
+	// 2. Центр - зона комфорта
+	e := trace.reduceSum(PointCenter, plus(PointDay, a), plus(PointMonth, b), plus(PointYear, c), plus(PointBottom, d))
+
+	// 3. Родовой квадрат
+	f := trace.reduceSum(PointTopLeft, plus(PointDay, a), plus(PointMonth, b))
+	g := trace.reduceSum(PointTopRight, plus(PointMonth, b), plus(PointYear, c))
+	h := trace.reduceSum(PointBottomRight, plus(PointYear, c), plus(PointBottom, d))
+	i := trace.reduceSum(PointBottomLeft, plus(PointBottom, d), plus(PointDay, a))
+
+	// 4. Внутренние точки по линиям от углов к центру
+	a1 := trace.reduceSum(PointDayInner, plus(PointDay, a), plus(PointCenter, e))
+	a2 := trace.reduceSum(PointDayOuter, plus(PointDay, a), plus(PointDayInner, a1))
+	b1 := trace.reduceSum(PointMonthInner, plus(PointMonth, b), plus(PointCenter, e))
+	b2 := trace.reduceSum(PointMonthOuter, plus(PointMonth, b), plus(PointMonthInner, b1))
+	c1 := trace.reduceSum(PointYearInner, plus(PointYear, c), plus(PointCenter, e))
+	c2 := trace.reduceSum(PointYearOuter, plus(PointYear, c), plus(PointYearInner, c1))
+	d1 := trace.reduceSum(PointTailInner, plus(PointBottom, d), plus(PointCenter, e))
+	d2 := trace.reduceSum(PointTailOuter, plus(PointBottom, d), plus(PointTailInner, d1))
+
+	// 5. Каналы отношений и денег
+	x := trace.reduceSum(PointBalance, plus(PointYearInner, c1), plus(PointTailInner, d1))
+	love := trace.reduceSum(PointLove, plus(PointBalance, x), plus(PointTailInner, d1))
+	money := trace.reduceSum(PointMoney, plus(PointBalance, x), plus(PointYearInner, c1))
+
+	// 6. Предназначения
+	sky := trace.reduceSum("sky", plus(PointMonth, b), plus(PointBottom, d))
+	earth := trace.reduceSum("earth", plus(PointDay, a), plus(PointYear, c))
+
+	values := map[string]int{
+		PointDay: a, PointMonth: b, PointYear: c, PointBottom: d, PointCenter: e,
+		PointTopLeft: f, PointTopRight: g, PointBottomRight: h, PointBottomLeft: i,
+		PointDayInner: a1, PointDayOuter: a2, PointMonthInner: b1, PointMonthOuter: b2,
+		PointYearInner: c1, PointYearOuter: c2, PointTailInner: d1, PointTailOuter: d2,
+		PointBalance: x, PointLove: love, PointMoney: money,
+	}
+
+	result := buildFullMatrix(values, MatrixPurposes{
+		Sky:      sky,
+		Earth:    earth,
+		Personal: trace.reduceSum("personal", plus("sky", sky), plus("earth", earth)),
+	})
+	result.BirthDate = &birthDate
+
+	// 7. Кармическая программа хвоста
+	program, err := DetectKarmicProgramWithTrace(d1, d2, d, trace)
+	if err != nil {
+		return nil, err
+	}
+	result.KarmicProgram = program
+
+	return result, nil
+}
+
+// CalculateCoupleMatrix рассчитывает матрицу пары: каждая точка - сумма
+// соответствующих точек матриц партнеров, приведенная к 1-22
+func CalculateCoupleMatrix(birthDate1, birthDate2 BirthDate) (*FullMatrix, error) {
+	return CalculateCoupleMatrixWithTrace(birthDate1, birthDate2, nil)
+}
+
+// CalculateCoupleMatrixWithTrace рассчитывает матрицу пары и записывает шаги расчета в trace
+func CalculateCoupleMatrixWithTrace(birthDate1, birthDate2 BirthDate, trace *Trace) (*FullMatrix, error) {
+	matrix1, err := CalculateFullMatrixWithTrace(birthDate1, trace.Scope("person1"))
+	if err != nil {
+		return nil, fmt.Errorf("person 1: %w", err)
+	}
+
+	matrix2, err := CalculateFullMatrixWithTrace(birthDate2, trace.Scope("person2"))
+	if err != nil {
+		return nil, fmt.Errorf("person 2: %w", err)
+	}
+
+	values := make(map[string]int, len(matrix1.Points))
+	for _, p1 := range matrix1.Points {
+		p2, _ := matrix2.Point(p1.ID)
+		values[p1.ID] = trace.reduceSum(p1.ID, plus("person1."+p1.ID, p1.Arcana), plus("person2."+p1.ID, p2.Arcana))
+	}
+
+	sky := trace.reduceSum("sky", plus("person1.sky", matrix1.Purposes.Sky), plus("person2.sky", matrix2.Purposes.Sky))
+	earth := trace.reduceSum("earth", plus("person1.earth", matrix1.Purposes.Earth), plus("person2.earth", matrix2.Purposes.Earth))
+
+	result := buildFullMatrix(values, MatrixPurposes{
+		Sky:      sky,
+		Earth:    earth,
+		Personal: trace.reduceSum("personal", plus("sky", sky), plus("earth", earth)),
+	})
+
+	// Кармическая программа хвоста пары
+	program, err := DetectKarmicProgramWithTrace(values[PointTailInner], values[PointTailOuter], values[PointBottom], trace)
+	if err != nil {
+		return nil, err
+	}
+	result.KarmicProgram = program
+
+	return result, nil
+}
+
+// matrixPointOrder задает порядок точек матрицы
+var matrixPointOrder = []string{
+	PointDay,
+	PointMonth,
+	PointYear,
+	PointBottom,
+	PointCenter,
+	PointTopLeft,
+	PointTopRight,
+	PointBottomRight,
+	PointBottomLeft,
+	PointDayInner,
+	PointDayOuter,
+	PointMonthInner,
+	PointMonthOuter,
+	PointYearInner,
+	PointYearOuter,
+	PointTailInner,
+	PointTailOuter,
+	PointBalance,
+	PointLove,
+	PointMoney,
+}
+
+// buildFullMatrix собирает матрицу из значений точек
+func buildFullMatrix(values map[string]int, purposes MatrixPurposes) *FullMatrix {
+	points := make([]MatrixPoint, 0, len(matrixPointOrder))
+	for _, id := range matrixPointOrder {
+		points = append(points, newMatrixPoint(id, values[id]))
+	}
+
+	return &FullMatrix{
+		Points:       points,
+		ComfortZone:  values[PointCenter],
+		KarmicTail:   []int{values[PointBottom], values[PointTailOuter], values[PointTailInner]},
+		LoveChannel:  []int{values[PointTailInner], values[PointLove], values[PointBalance]},
+		MoneyChannel: []int{values[PointYearInner], values[PointMoney], values[PointBalance]},
+		Purposes:     purposes,
+	}
+}
+
+// matrixLayout задает координаты точек октаграммы.
+// Внешние точки лежат на радиусе 0.5, вторые - на 0.4, первые - на 0.3
+var matrixLayout = map[string][2]float64{
+	PointDay:         {0, 0.5},
+	PointMonth:       {0.5, 0},
+	PointYear:        {1, 0.5},
+	PointBottom:      {0.5, 1},
+	PointCenter:      {0.5, 0.5},
+	PointTopLeft:     {0.1464, 0.1464},
+	PointTopRight:    {0.8536, 0.1464},
+	PointBottomRight: {0.8536, 0.8536},
+	PointBottomLeft:  {0.1464, 0.8536},
+	PointDayInner:    {0.2, 0.5},
+	PointDayOuter:    {0.1, 0.5},
+	PointMonthInner:  {0.5, 0.2},
+	PointMonthOuter:  {0.5, 0.1},
+	PointYearInner:   {0.8, 0.5},
+	PointYearOuter:   {0.9, 0.5},
+	PointTailInner:   {0.5, 0.8},
+	PointTailOuter:   {0.5, 0.9},
+	PointBalance:     {0.65, 0.65},
+	PointLove:        {0.575, 0.725},
+	PointMoney:       {0.725, 0.575},
+}
+
+// newMatrixPoint создает точку матрицы с координатами из раскладки.
+// Название точки берется из каталога сообщений по ключу matrix.point.<id>
+func newMatrixPoint(id string, arcana int) MatrixPoint {
+	pos := matrixLayout[id]
+	return MatrixPoint{
+		ID:         id,
+		Name:       i18n.T("matrix.point." + id),
+		Arcana:     arcana,
+		ArcanaName: GetArcanaName(arcana),
+		X:          pos[0],
+		Y:          pos[1],
+	}
+}
@@ -0,0 +1,165 @@
+package calculator
+
+import (
+	"fmt"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+const (
+	// MinGroupSize - минимальное количество участников группы
+	MinGroupSize = 3
+	// MaxGroupSize - максимальное количество участников группы
+	MaxGroupSize = 10
+)
+
+// GroupMember представляет участника группы
+type GroupMember struct {
+	Name   string
+	Matrix *MatrixFate
+}
+
+// GroupCompatibilityResult представляет результат расчета совместимости группы
+type GroupCompatibilityResult struct {
+	Members         []i18n.Text         `json:"members"`
+	ScoreMatrix     [][]int             `json:"scoreMatrix"`
+	Pairs           []PairCompatibility `json:"pairs"`
+	AverageScore    int                 `json:"averageScore"`
+	GroupArcana     int                 `json:"groupArcana"`
+	GroupArcanaName i18n.Text           `json:"groupArcanaName"`
+	MostHarmonious  PairCompatibility   `json:"mostHarmonious"`
+	LeastHarmonious PairCompatibility   `json:"leastHarmonious"`
+	Strengths       []i18n.Text         `json:"strengths"`
+	Challenges      []i18n.Text         `json:"challenges"`
+}
+
+// PairCompatibility представляет совместимость пары участников группы
+type PairCompatibility struct {
+	Person1 int                 `json:"person1"`
+	Person2 int                 `json:"person2"`
+	Result  CompatibilityResult `json:"result"`
+}
+
+// CalculateGroupCompatibility рассчитывает попарную совместимость группы из 3-10 человек
+func CalculateGroupCompatibility(members []GroupMember) (*GroupCompatibilityResult, error) {
+	return CalculateGroupCompatibilityWithTrace(members, nil)
+}
+
+// CalculateGroupCompatibilityWithTrace рассчитывает совместимость группы
+// и записывает в trace расчет каждой пары и показатели группы
+func CalculateGroupCompatibilityWithTrace(members []GroupMember, trace *Trace) (*GroupCompatibilityResult, error) {
+	if len(members) < MinGroupSize || len(members) > MaxGroupSize {
+		return nil, fmt.Errorf("group must contain from %d to %d people", MinGroupSize, MaxGroupSize)
+	}
+
+	n := len(members)
+	names := make([]i18n.Text, n)
+	scoreMatrix := make([][]int, n)
+	for i := range scoreMatrix {
+		scoreMatrix[i] = make([]int, n)
+		scoreMatrix[i][i] = 100
+		names[i] = i18n.Raw(members[i].Name)
+		if members[i].Name == "" {
+			names[i] = i18n.T("group.member", i18n.Params{"number": i + 1})
+		}
+	}
+
+	// 1. Попарная совместимость по профилю по умолчанию
+	pairs := make([]PairCompatibility, 0, n*(n-1)/2)
+	scores := make([]TraceOperand, 0, n*(n-1)/2)
+	mains := make([]TraceOperand, 0, n)
+	for i := 0; i < n; i++ {
+		mains = append(mains, plus(fmt.Sprintf("members[%d].main", i), members[i].Matrix.Main))
+		for j := i + 1; j < n; j++ {
+			pairName := fmt.Sprintf("pairs[%d,%d]", i, j)
+			result, err := CalculateCompatibilityWithTrace(members[i].Matrix, members[j].Matrix, "", trace.Scope(pairName))
+			if err != nil {
+				return nil, err
+			}
+
+			scoreMatrix[i][j] = result.OverallScore
+			scoreMatrix[j][i] = result.OverallScore
+			scores = append(scores, plus(pairName+".overallScore", result.OverallScore))
+
+			pairs = append(pairs, PairCompatibility{
+				Person1: i,
+				Person2: j,
+				Result:  *result,
+			})
+		}
+	}
+
+	// 2. Самая гармоничная и самая сложная пары
+	most, least := pairs[0], pairs[0]
+	for _, pair := range pairs[1:] {
+		if pair.Result.OverallScore > most.Result.OverallScore {
+			most = pair
+		}
+		if pair.Result.OverallScore < least.Result.OverallScore {
+			least = pair
+		}
+	}
+
+	// 3. Коллективный аркан группы
+	groupArcana := trace.reduceSum("groupArcana", mains...)
+	totalScore := trace.sum("totalScore", scores...)
+	averageScore := totalScore / len(pairs)
+	trace.record(TraceStep{
+		Name:       "averageScore",
+		Formula:    "totalScore / pairs",
+		Operands:   []TraceOperand{plus("totalScore", totalScore), plus("pairs", len(pairs))},
+		Value:      averageScore,
+		Expression: fmt.Sprintf("%d / %d = %d", totalScore, len(pairs), averageScore),
+	})
+
+	// 4. Признаки пар по правилам
+	favorablePairs := 0
+	towerPairs := 0
+	for _, pair := range pairs {
+		facts := rules.Facts{}
+		facts.Set("score", pair.Result.OverallScore)
+		facts.Set("karmicTask", pair.Result.KarmicTask)
+		evaluated := evaluateRules(RulesGroupPair, facts, nil)
+
+		pairTrace := trace.Scope(fmt.Sprintf("pairs[%d,%d]", pair.Person1, pair.Person2))
+		pairTrace.rule("favorable", RulesGroupPair, "favorable", evaluated)
+		pairTrace.rule("tower", RulesGroupPair, "tower", evaluated)
+		if evaluated.Bool("favorable") {
+			favorablePairs++
+		}
+		if evaluated.Bool("tower") {
+			towerPairs++
+		}
+	}
+
+	// 5. Сильные стороны и вызовы группы
+	facts := rules.Facts{}
+	facts.Set("size", n)
+	facts.Set("pairs", len(pairs))
+	facts.Set("averageScore", averageScore)
+	facts.Set("scoreSpread", trace.sum("scoreSpread",
+		plus("mostHarmonious.overallScore", most.Result.OverallScore),
+		minus("leastHarmonious.overallScore", least.Result.OverallScore)))
+	facts.Set("favorablePairs", favorablePairs)
+	facts["favorableShare"] = float64(favorablePairs) / float64(len(pairs))
+	facts.Set("towerPairs", towerPairs)
+	facts.Set("groupArcana", groupArcana)
+	evaluated := evaluateRules(RulesGroup, facts, i18n.Params{
+		"leastPerson1": names[least.Person1],
+		"leastPerson2": names[least.Person2],
+	})
+
+	return &GroupCompatibilityResult{
+		Members:         names,
+		ScoreMatrix:     scoreMatrix,
+		Pairs:           pairs,
+		AverageScore:    averageScore,
+		GroupArcana:     groupArcana,
+		GroupArcanaName: GetArcanaName(groupArcana),
+		MostHarmonious:  most,
+		LeastHarmonious: least,
+		Strengths:       evaluated.Texts("strengths"),
+		Challenges:      evaluated.Texts("challenges"),
+	}, nil
+}
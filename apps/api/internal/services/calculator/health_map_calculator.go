@@ -0,0 +1,121 @@
+package calculator
+
+import (
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// HealthMap представляет карту здоровья (чакры) по дате рождения
+type HealthMap struct {
+	Chakras []ChakraRow `json:"chakras"`
+	Totals  ChakraRow   `json:"totals"`
+}
+
+// ChakraRow представляет строку карты здоровья
+type ChakraRow struct {
+	Chakra         string    `json:"chakra"`
+	Name           i18n.Text `json:"name"`
+	Physical       int       `json:"physical"`
+	Energy         int       `json:"energy"`
+	Emotions       int       `json:"emotions"`
+	Sum            int       `json:"sum"`
+	Overloaded     bool      `json:"overloaded"`
+	Interpretation i18n.Text `json:"interpretation"`
+}
+
+// CalculateHealthMap рассчитывает карту здоровья по дате рождения
+func CalculateHealthMap(birthDate BirthDate) (*HealthMap, error) {
+	return CalculateHealthMapWithTrace(birthDate, nil)
+}
+
+// CalculateHealthMapWithTrace рассчитывает карту здоровья и записывает
+// в trace точки матрицы и расчет каждой чакры
+func CalculateHealthMapWithTrace(birthDate BirthDate, trace *Trace) (*HealthMap, error) {
+	matrix, err := CalculateFullMatrixWithTrace(birthDate, trace.Scope("fullMatrix"))
+	if err != nil {
+		return nil, err
+	}
+
+	point := func(id string) TraceOperand {
+		p, _ := matrix.Point(id)
+		return plus(id, p.Arcana)
+	}
+
+	a1, b1, e := point(PointDayInner), point(PointMonthInner), point(PointCenter)
+
+	// Физика - линия дня (A, A2, A1) и нижняя линия (D1, D), энергия - линия
+	// месяца (B, B2, B1) и линия года (C1, C); в анахате и манипуре - центр E
+	rows := []ChakraRow{
+		newChakraRow(trace, "sahasrara", point(PointDay), point(PointMonth)),
+		newChakraRow(trace, "ajna", point(PointDayOuter), point(PointMonthOuter)),
+		newChakraRow(trace, "vishuddha", a1, b1),
+		newChakraRow(trace, "anahata",
+			plus("anahata.physical", trace.reduceSum("anahata.physical", a1, e)),
+			plus("anahata.energy", trace.reduceSum("anahata.energy", b1, e))),
+		newChakraRow(trace, "manipura", e, e),
+		newChakraRow(trace, "svadhisthana", point(PointTailInner), point(PointYearInner)),
+		newChakraRow(trace, "muladhara", point(PointBottom), point(PointYear)),
+	}
+
+	// Итог - сумма каждого столбца
+	physical := make([]TraceOperand, 0, len(rows))
+	energy := make([]TraceOperand, 0, len(rows))
+	emotions := make([]TraceOperand, 0, len(rows))
+	for _, row := range rows {
+		physical = append(physical, plus(row.Chakra+".physical", row.Physical))
+		energy = append(energy, plus(row.Chakra+".energy", row.Energy))
+		emotions = append(emotions, plus(row.Chakra+".emotions", row.Emotions))
+	}
+
+	totals := ChakraRow{
+		Chakra:   "total",
+		Name:     i18n.T("health.total.name"),
+		Physical: trace.reduceSum("total.physical", physical...),
+		Energy:   trace.reduceSum("total.energy", energy...),
+		Emotions: trace.reduceSum("total.emotions", emotions...),
+	}
+	totals.Sum = trace.sum("total.sum",
+		plus("total.physical", totals.Physical), plus("total.energy", totals.Energy), plus("total.emotions", totals.Emotions))
+	totals.Overloaded = isOverloaded(trace.Scope("total"), totals.Physical, totals.Energy, totals.Emotions)
+	totals.Interpretation = i18n.T("health.total.description")
+
+	return &HealthMap{
+		Chakras: rows,
+		Totals:  totals,
+	}, nil
+}
+
+// newChakraRow создает строку карты здоровья с интерпретацией
+func newChakraRow(trace *Trace, chakra string, physical, energy TraceOperand) ChakraRow {
+	scope := trace.Scope(chakra)
+	emotions := scope.reduceSum("emotions", physical, energy)
+	overloaded := isOverloaded(scope, int(physical.Value), int(energy.Value), emotions)
+
+	// Описание зоны ответственности чакры
+	interpretation := i18n.T("health.chakra." + chakra + ".description")
+	if overloaded {
+		interpretation = i18n.T("health.chakra.overloaded", i18n.Params{"description": interpretation})
+	}
+
+	return ChakraRow{
+		Chakra:         chakra,
+		Name:           i18n.T("health.chakra." + chakra + ".name"),
+		Physical:       int(physical.Value),
+		Energy:         int(energy.Value),
+		Emotions:       emotions,
+		Sum:            scope.sum("sum", plus("physical", int(physical.Value)), plus("energy", int(energy.Value)), plus("emotions", emotions)),
+		Overloaded:     overloaded,
+		Interpretation: interpretation,
+	}
+}
+
+// isOverloaded проверяет по правилам карты здоровья, перегружена ли чакра
+func isOverloaded(trace *Trace, physical, energy, emotions int) bool {
+	facts := rules.Facts{}
+	facts.Set("physical", physical)
+	facts.Set("energy", energy)
+	facts.Set("emotions", emotions)
+	evaluated := evaluateRules(RulesHealth, facts, nil)
+	trace.rule("overloaded", RulesHealth, "overloaded", evaluated)
+	return evaluated.Bool("overloaded")
+}
@@ -0,0 +1,74 @@
+package calculator
+
+import (
+	"fmt"
+	"strings"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// KarmicProgramUnknown - программа цепочки, которой нет в каталоге
+const KarmicProgramUnknown = "unknown"
+
+// Кармические программы хвоста. Цепочки задаются правилами karmic_tail
+var karmicPrograms = [13]string{
+	"magician", "betrayal", "rebel", "overseer", "warrior", "love_addiction", "prisoner",
+	"family_disappointment", "victim", "forbidden_love", "unborn_child", "love_spell",
+	KarmicProgramUnknown,
+}
+
+// KarmicProgram представляет кармическую программу хвоста и ее прочтение
+type KarmicProgram struct {
+	// Chain - цепочка от центра к нижней точке: D1, D2, D (18-9-9). Порядок
+	// обратный FullMatrix.KarmicTail
+	Chain       []int       `json:"chain"`
+	ChainNames  []i18n.Text `json:"chainNames"`
+	Program     string      `json:"program"`
+	Known       bool        `json:"known"`
+	Name        i18n.Text   `json:"name"`
+	Description i18n.Text   `json:"description"`
+	Work        i18n.Text   `json:"work"`
+}
+
+// DetectKarmicProgram находит программу кармического хвоста по точкам D1, D2 и D
+func DetectKarmicProgram(inner, outer, bottom int) (*KarmicProgram, error) {
+	return DetectKarmicProgramWithTrace(inner, outer, bottom, nil)
+}
+
+// DetectKarmicProgramWithTrace находит программу кармического хвоста
+// и записывает выбранное правило в trace
+func DetectKarmicProgramWithTrace(inner, outer, bottom int, trace *Trace) (*KarmicProgram, error) {
+	chain := []int{inner, outer, bottom}
+
+	names := make([]i18n.Text, len(chain))
+	codes := make([]string, len(chain))
+	for i, arcana := range chain {
+		if arcana < 1 || arcana > 22 {
+			return nil, fmt.Errorf("karmic tail arcana must be between 1 and 22, got %d", arcana)
+		}
+		names[i] = GetArcanaName(arcana)
+		codes[i] = fmt.Sprint(arcana)
+	}
+
+	facts := rules.Facts{}
+	facts.Set(PointTailInner, inner)
+	facts.Set(PointTailOuter, outer)
+	facts.Set(PointBottom, bottom)
+	result := evaluateRules(RulesKarmicTail, facts, nil)
+	trace.rule("karmicProgram", RulesKarmicTail, "program", result)
+
+	program := result.Label("program")
+	key := "tail.program." + program
+	params := i18n.Params{"chain": strings.Join(codes, "-")}
+
+	return &KarmicProgram{
+		Chain:       chain,
+		ChainNames:  names,
+		Program:     program,
+		Known:       program != KarmicProgramUnknown,
+		Name:        i18n.T(key+".name", params),
+		Description: i18n.T(key+".description", params),
+		Work:        i18n.T(key+".work", params),
+	}, nil
+}
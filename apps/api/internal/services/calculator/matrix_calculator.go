@@ -1,56 +1,51 @@
 package calculator
 
 import (
-	"fmt"
 	"strconv"
-	"strings"
-	"time"
+
+	"arcanum/internal/i18n"
 )
 
 // MatrixFate представляет результат расчета Матрицы Судьбы
 type MatrixFate struct {
-	Main      int    `json:"main"`
-	Social    int    `json:"social"`
-	Spiritual int    `json:"spiritual"`
-	Tail      int    `json:"tail"`
-	BirthDate string `json:"birthDate"`
+	Main      int       `json:"main"`
+	Social    int       `json:"social"`
+	Spiritual int       `json:"spiritual"`
+	Tail      int       `json:"tail"`
+	BirthDate BirthDate `json:"birthDate"`
 }
 
 // CalculateMatrixFate рассчитывает Матрицу Судьбы по дате рождения
-func CalculateMatrixFate(birthDate string) (*MatrixFate, error) {
-	// Валидация формата даты (DD.MM.YYYY)
-	if err := validateDateFormat(birthDate); err != nil {
-		return nil, err
-	}
+func CalculateMatrixFate(birthDate BirthDate) (*MatrixFate, error) {
+	return CalculateMatrixFateWithTrace(birthDate, nil)
+}
 
-	// Извлекаем все цифры из даты
-	digits := extractDigits(birthDate)
-	if len(digits) == 0 {
-		return nil, fmt.Errorf("no digits found in birth date")
+// CalculateMatrixFateWithTrace рассчитывает Матрицу Судьбы и записывает шаги расчета в trace
+func CalculateMatrixFateWithTrace(birthDate BirthDate, trace *Trace) (*MatrixFate, error) {
+	if birthDate.IsZero() {
+		return nil, ErrEmptyBirthDate
 	}
 
-	// Сумма всех цифр
-	total := sumDigits(digits)
+	// Извлекаем все цифры из даты и суммируем их
+	total := trace.digitSum("total", "birthDate", extractDigits(birthDate.String()))
 
 	// Основное предназначение (main)
-	main := reduceTo22(total)
+	main := trace.reduceSum("main", plus("total", total))
 	if main == 0 {
-		main = 22
+		main = trace.assign("main", plus("22", 22))
 	}
 
 	// Извлекаем день и год
-	parts := strings.Split(birthDate, ".")
-	day, _ := strconv.Atoi(parts[0])
-	year, _ := strconv.Atoi(parts[2])
+	day, year := birthDate.Day(), birthDate.Year()
 
 	// Социальная реализация (social)
-	social := reduceTo22(day + year)
+	social := trace.reduceSum("social", plus("day", day), plus("year", year))
 
 	// Духовный путь (spiritual)
-	spiritual := reduceTo22(main + social)
+	spiritual := trace.reduceSum("spiritual", plus("main", main), plus("social", social))
 
 	// Кармический хвост (tail)
-	tail := reduceTo22(total - day)
+	tail := trace.reduceSum("tail", plus("total", total), minus("day", day))
 
 	return &MatrixFate{
 		Main:      main,
@@ -61,6 +56,56 @@ func CalculateMatrixFate(birthDate string) (*MatrixFate, error) {
 	}, nil
 }
 
+// MatrixReport - полный результат /calculate/matrix: основные арканы с названиями и полная матрица
+type MatrixReport struct {
+	Main        int               `json:"main"`
+	Social      int               `json:"social"`
+	Spiritual   int               `json:"spiritual"`
+	Tail        int               `json:"tail"`
+	ArcanaNames MatrixArcanaNames `json:"arcanaNames"`
+	FullMatrix  *FullMatrix       `json:"fullMatrix"`
+}
+
+// MatrixArcanaNames содержит названия основных арканов матрицы
+type MatrixArcanaNames struct {
+	Main      i18n.Text `json:"main"`
+	Social    i18n.Text `json:"social"`
+	Spiritual i18n.Text `json:"spiritual"`
+	Tail      i18n.Text `json:"tail"`
+}
+
+// CalculateMatrixReport рассчитывает Матрицу Судьбы вместе с полной матрицей
+func CalculateMatrixReport(birthDate BirthDate) (*MatrixReport, error) {
+	return CalculateMatrixReportWithTrace(birthDate, nil)
+}
+
+// CalculateMatrixReportWithTrace рассчитывает Матрицу Судьбы с полной матрицей и записывает шаги в trace
+func CalculateMatrixReportWithTrace(birthDate BirthDate, trace *Trace) (*MatrixReport, error) {
+	result, err := CalculateMatrixFateWithTrace(birthDate, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	fullMatrix, err := CalculateFullMatrixWithTrace(birthDate, trace.Scope("fullMatrix"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MatrixReport{
+		Main:      result.Main,
+		Social:    result.Social,
+		Spiritual: result.Spiritual,
+		Tail:      result.Tail,
+		ArcanaNames: MatrixArcanaNames{
+			Main:      GetArcanaName(result.Main),
+			Social:    GetArcanaName(result.Social),
+			Spiritual: GetArcanaName(result.Spiritual),
+			Tail:      GetArcanaName(result.Tail),
+		},
+		FullMatrix: fullMatrix,
+	}, nil
+}
+
 // reduceTo22 приводит число к диапазону 1-22
 func reduceTo22(num int) int {
 	if num <= 0 {
@@ -101,60 +146,7 @@ func sumDigits(digits []int) int {
 	return sum
 }
 
-// validateDateFormat проверяет формат даты DD.MM.YYYY
-func validateDateFormat(dateStr string) error {
-	parts := strings.Split(dateStr, ".")
-	if len(parts) != 3 {
-		return fmt.Errorf("invalid date format, expected DD.MM.YYYY")
-	}
-
-	day, err := strconv.Atoi(parts[0])
-	if err != nil || day < 1 || day > 31 {
-		return fmt.Errorf("invalid day: %s", parts[0])
-	}
-
-	month, err := strconv.Atoi(parts[1])
-	if err != nil || month < 1 || month > 12 {
-		return fmt.Errorf("invalid month: %s", parts[1])
-	}
-
-	year, err := strconv.Atoi(parts[2])
-	if err != nil || year < 1900 || year > time.Now().Year() {
-		return fmt.Errorf("invalid year: %s", parts[2])
-	}
-
-	return nil
-}
-
-// GetArcanaName возвращает название аркана по номеру
-func GetArcanaName(number int) string {
-	arcanaNames := map[int]string{
-		1:  "Маг",
-		2:  "Верховная Жрица",
-		3:  "Императрица",
-		4:  "Император",
-		5:  "Иерофант",
-		6:  "Влюбленные",
-		7:  "Колесница",
-		8:  "Справедливость",
-		9:  "Отшельник",
-		10: "Колесо Фортуны",
-		11: "Сила",
-		12: "Повешенный",
-		13: "Смерть",
-		14: "Умеренность",
-		15: "Дьявол",
-		16: "Башня",
-		17: "Звезда",
-		18: "Луна",
-		19: "Солнце",
-		20: "Суд",
-		21: "Мир",
-		22: "Шут",
-	}
-
-	if name, ok := arcanaNames[number]; ok {
-		return name
-	}
-	return "Неизвестный аркан"
+// GetArcanaName возвращает локализуемое название аркана по номеру
+func GetArcanaName(number int) i18n.Text {
+	return i18n.ArcanaName(number)
 }
@@ -0,0 +1,274 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"arcanum/internal/i18n"
+)
+
+const (
+	// DefaultMatrixSearchLimit - количество дат в ответе по умолчанию
+	DefaultMatrixSearchLimit = 100
+	// MaxMatrixSearchLimit - максимальное количество дат в ответе
+	MaxMatrixSearchLimit = 1000
+)
+
+// matrixPoints - точки матрицы судьбы в порядке хранения в индексе
+var matrixPoints = [4]string{"main", "social", "spiritual", "tail"}
+
+// MatrixSearchQuery описывает условия обратного поиска по матрице судьбы.
+// Нулевой аркан означает любое значение точки, нулевые даты - границы индекса
+type MatrixSearchQuery struct {
+	Main      int
+	Social    int
+	Spiritual int
+	Tail      int
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
+
+// MatrixSearchResult представляет результат обратного поиска
+type MatrixSearchResult struct {
+	Constraints  MatrixConstraints  `json:"constraints"`
+	From         string             `json:"from"`
+	To           string             `json:"to"`
+	Checked      int                `json:"checked"`
+	Total        int                `json:"total"`
+	Share        float64            `json:"share"`
+	Dates        []string           `json:"dates"`
+	Distribution MatrixDistribution `json:"distribution"`
+}
+
+// MatrixConstraints представляет заданные арканы точек
+type MatrixConstraints struct {
+	Main      int `json:"main,omitempty"`
+	Social    int `json:"social,omitempty"`
+	Spiritual int `json:"spiritual,omitempty"`
+	Tail      int `json:"tail,omitempty"`
+}
+
+// MatrixDistribution представляет распределение арканов и десятилетий среди найденных дат
+type MatrixDistribution struct {
+	Main      []ArcanaCount `json:"main"`
+	Social    []ArcanaCount `json:"social"`
+	Spiritual []ArcanaCount `json:"spiritual"`
+	Tail      []ArcanaCount `json:"tail"`
+	Decades   []DecadeCount `json:"decades"`
+}
+
+// ArcanaCount представляет количество дат с арканом
+type ArcanaCount struct {
+	Arcana     int       `json:"arcana"`
+	ArcanaName i18n.Text `json:"arcanaName"`
+	Count      int       `json:"count"`
+}
+
+// DecadeCount представляет количество дат в десятилетии
+type DecadeCount struct {
+	Decade int `json:"decade"`
+	Count  int `json:"count"`
+}
+
+// matrixIndex - арканы всех допустимых дат рождения, по одной записи на день
+// начиная с 1 января минимального года. Для каждой точки и аркана хранится
+// список номеров записей, поэтому поиск перебирает только самый короткий из них
+type matrixIndex struct {
+	minYear  int
+	start    time.Time
+	end      time.Time
+	arcana   [][4]uint8
+	postings [4][23][]int32
+}
+
+var (
+	matrixIndexMu      sync.Mutex
+	currentMatrixIndex *matrixIndex
+)
+
+// loadMatrixIndex возвращает индекс по датам до today включительно.
+// Индекс строится при первом поиске и перестраивается, когда наступает
+// новый день или меняется минимальный год рождения
+func loadMatrixIndex(today time.Time) *matrixIndex {
+	matrixIndexMu.Lock()
+	defer matrixIndexMu.Unlock()
+
+	index := currentMatrixIndex
+	if index == nil || index.minYear != MinBirthYear() || !index.end.Equal(today) {
+		index = buildMatrixIndex(MinBirthYear(), today)
+		currentMatrixIndex = index
+	}
+	return index
+}
+
+// buildMatrixIndex рассчитывает матрицы всех дат с 1 января minYear по end
+func buildMatrixIndex(minYear int, end time.Time) *matrixIndex {
+	start := time.Date(minYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	index := &matrixIndex{minYear: minYear, start: start, end: end}
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		matrix, err := CalculateMatrixFate(BirthDate{t: date})
+		if err != nil {
+			panic(fmt.Sprintf("calculator: matrix index: %v", err))
+		}
+
+		n := int32(len(index.arcana))
+		points := [4]uint8{uint8(matrix.Main), uint8(matrix.Social), uint8(matrix.Spiritual), uint8(matrix.Tail)}
+		index.arcana = append(index.arcana, points)
+		for p, arcana := range points {
+			index.postings[p][arcana] = append(index.postings[p][arcana], n)
+		}
+	}
+	return index
+}
+
+// position возвращает номер записи даты в индексе
+func (index *matrixIndex) position(date time.Time) int {
+	return int(date.Sub(index.start).Hours() / 24)
+}
+
+// SearchMatrix находит даты рождения, матрица которых содержит заданные
+// арканы. Поиск идет по датам от минимального года рождения до сегодняшнего
+// дня (UTC); незаданные точки принимают любое значение
+func SearchMatrix(query MatrixSearchQuery) (*MatrixSearchResult, error) {
+	constraints := [4]int{query.Main, query.Social, query.Spiritual, query.Tail}
+	for p, arcana := range constraints {
+		if arcana < 0 || arcana > 22 {
+			return nil, fmt.Errorf("%s arcana must be between 1 and 22", matrixPoints[p])
+		}
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultMatrixSearchLimit
+	}
+	if query.Limit < 1 || query.Limit > MaxMatrixSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxMatrixSearchLimit)
+	}
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	today, err := Today(DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+	index := loadMatrixIndex(today)
+
+	// 1. Диапазон дат ограничивается границами индекса
+	from, to := index.start, index.end
+	if !query.From.IsZero() && query.From.After(from) {
+		from = time.Date(query.From.Year(), query.From.Month(), query.From.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if !query.To.IsZero() && query.To.Before(to) {
+		to = time.Date(query.To.Year(), query.To.Month(), query.To.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("search range is empty: dates must be between %s and %s",
+			index.start.Format(birthDateLayout), index.end.Format(birthDateLayout))
+	}
+	lo, hi := index.position(from), index.position(to)+1
+
+	// 2. Кандидаты - самый короткий список среди заданных точек
+	var candidates []int32
+	constrained := false
+	for p, arcana := range constraints {
+		if arcana == 0 {
+			continue
+		}
+		if list := index.postings[p][arcana]; !constrained || len(list) < len(candidates) {
+			candidates = list
+		}
+		constrained = true
+	}
+	if constrained {
+		first := sort.Search(len(candidates), func(i int) bool { return int(candidates[i]) >= lo })
+		last := sort.Search(len(candidates), func(i int) bool { return int(candidates[i]) >= hi })
+		candidates = candidates[first:last]
+	}
+
+	// 3. Проверка остальных точек и статистика
+	var counts [4][23]int
+	decades := map[int]int{}
+	dates := make([]string, 0, query.Limit)
+	total := 0
+	match := func(n int) {
+		points := index.arcana[n]
+		for p, arcana := range constraints {
+			if arcana != 0 && int(points[p]) != arcana {
+				return
+			}
+		}
+
+		date := index.start.AddDate(0, 0, n)
+		if total >= query.Offset && len(dates) < query.Limit {
+			dates = append(dates, date.Format(birthDateLayout))
+		}
+		total++
+		for p, arcana := range points {
+			counts[p][arcana]++
+		}
+		decades[date.Year()/10*10]++
+	}
+	if constrained {
+		for _, n := range candidates {
+			match(int(n))
+		}
+	} else {
+		for n := lo; n < hi; n++ {
+			match(n)
+		}
+	}
+
+	checked := hi - lo
+	return &MatrixSearchResult{
+		Constraints: MatrixConstraints{
+			Main:      query.Main,
+			Social:    query.Social,
+			Spiritual: query.Spiritual,
+			Tail:      query.Tail,
+		},
+		From:    from.Format(birthDateLayout),
+		To:      to.Format(birthDateLayout),
+		Checked: checked,
+		Total:   total,
+		Share:   float64(total*10000/checked) / 100,
+		Dates:   dates,
+		Distribution: MatrixDistribution{
+			Main:      arcanaCounts(counts[0]),
+			Social:    arcanaCounts(counts[1]),
+			Spiritual: arcanaCounts(counts[2]),
+			Tail:      arcanaCounts(counts[3]),
+			Decades:   decadeCounts(decades),
+		},
+	}, nil
+}
+
+// arcanaCounts возвращает ненулевые количества дат по арканам от 1 до 22
+func arcanaCounts(counts [23]int) []ArcanaCount {
+	result := make([]ArcanaCount, 0, 22)
+	for arcana := 1; arcana <= 22; arcana++ {
+		if counts[arcana] == 0 {
+			continue
+		}
+		result = append(result, ArcanaCount{
+			Arcana:     arcana,
+			ArcanaName: GetArcanaName(arcana),
+			Count:      counts[arcana],
+		})
+	}
+	return result
+}
+
+// decadeCounts возвращает количества дат по десятилетиям по возрастанию
+func decadeCounts(decades map[int]int) []DecadeCount {
+	result := make([]DecadeCount, 0, len(decades))
+	for decade, count := range decades {
+		result = append(result, DecadeCount{Decade: decade, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Decade < result[j].Decade })
+	return result
+}
@@ -0,0 +1,263 @@
+package calculator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Алфавиты для расчета Числа Имени
+const (
+	AlphabetAuto     = "auto"
+	AlphabetCyrillic = "cyrillic"
+	AlphabetLatin    = "latin"
+)
+
+// Способы приведения чисел
+const (
+	ReductionArcana     = "arcana"     // 1-22 через reduceTo22
+	ReductionNumerology = "numerology" // 1-9 с мастер-числами 11, 22, 33
+)
+
+// NameNumber представляет результат расчета Числа Имени
+type NameNumber struct {
+	FullName    string     `json:"fullName"`
+	Alphabet    string     `json:"alphabet"`
+	Reduction   string     `json:"reduction"`
+	Expression  int        `json:"expression"`
+	SoulUrge    int        `json:"soulUrge"`
+	Personality int        `json:"personality"`
+	Parts       []NamePart `json:"parts"`
+}
+
+// NamePart представляет часть имени (имя, отчество, фамилия) с разбором по буквам
+type NamePart struct {
+	Part    string        `json:"part"`
+	Value   string        `json:"value"`
+	Sum     int           `json:"sum"`
+	Number  int           `json:"number"`
+	Letters []LetterValue `json:"letters"`
+}
+
+// LetterValue представляет числовое значение буквы
+type LetterValue struct {
+	Letter string `json:"letter"`
+	Value  int    `json:"value"`
+	Vowel  bool   `json:"vowel"`
+}
+
+// cyrillicTable - стандартная нумерологическая таблица кириллицы: 31 буква без Ё и Й.
+// normalizeName заменяет Ё и Й на Е (6) и И (1) до поиска в таблице
+var cyrillicTable = map[rune]int{
+	'а': 1, 'б': 2, 'в': 3, 'г': 4, 'д': 5, 'е': 6, 'ж': 8, 'з': 9,
+	'и': 1, 'к': 3, 'л': 4, 'м': 5, 'н': 6, 'о': 7, 'п': 8, 'р': 9,
+	'с': 1, 'т': 2, 'у': 3, 'ф': 4, 'х': 5, 'ц': 6, 'ч': 7, 'ш': 8, 'щ': 9,
+	'ъ': 1, 'ы': 2, 'ь': 3, 'э': 4, 'ю': 5, 'я': 6,
+}
+
+// latinTable - пифагорейская таблица латиницы
+var latinTable = map[rune]int{
+	'a': 1, 'b': 2, 'c': 3, 'd': 4, 'e': 5, 'f': 6, 'g': 7, 'h': 8, 'i': 9,
+	'j': 1, 'k': 2, 'l': 3, 'm': 4, 'n': 5, 'o': 6, 'p': 7, 'q': 8, 'r': 9,
+	's': 1, 't': 2, 'u': 3, 'v': 4, 'w': 5, 'x': 6, 'y': 7, 'z': 8,
+}
+
+var (
+	cyrillicVowels = "аеиоуыэюя"
+	latinVowels    = "aeiou"
+)
+
+// CalculateNameNumber рассчитывает Число Имени по ФИО
+func CalculateNameNumber(firstName, patronymic, lastName, alphabet, reduction string) (*NameNumber, error) {
+	return CalculateNameNumberWithTrace(firstName, patronymic, lastName, alphabet, reduction, nil)
+}
+
+// CalculateNameNumberWithTrace рассчитывает Число Имени и записывает в trace
+// суммы букв каждой части и приведение итоговых чисел
+func CalculateNameNumberWithTrace(firstName, patronymic, lastName, alphabet, reduction string, trace *Trace) (*NameNumber, error) {
+	if strings.TrimSpace(firstName) == "" {
+		return nil, fmt.Errorf("first name is required")
+	}
+
+	if reduction == "" {
+		reduction = ReductionArcana
+	}
+	if reduction != ReductionArcana && reduction != ReductionNumerology {
+		return nil, fmt.Errorf("unknown reduction: %s", reduction)
+	}
+
+	parts := []struct {
+		part  string
+		value string
+	}{
+		{"firstName", firstName},
+		{"patronymic", patronymic},
+		{"lastName", lastName},
+	}
+
+	// Нормализуем все части имени и определяем алфавит
+	normalized := make([]string, len(parts))
+	for i, p := range parts {
+		normalized[i] = normalizeName(p.value)
+	}
+
+	if alphabet == "" || alphabet == AlphabetAuto {
+		alphabet = detectAlphabet(strings.Join(normalized, ""))
+	}
+
+	var table map[rune]int
+	var vowelLetters string
+	switch alphabet {
+	case AlphabetCyrillic:
+		table, vowelLetters = cyrillicTable, cyrillicVowels
+	case AlphabetLatin:
+		table, vowelLetters = latinTable, latinVowels
+	default:
+		return nil, fmt.Errorf("unknown alphabet: %s", alphabet)
+	}
+
+	result := &NameNumber{
+		Alphabet:  alphabet,
+		Reduction: reduction,
+		Parts:     make([]NamePart, 0, len(parts)),
+	}
+
+	var total, vowels, consonants []TraceOperand
+	fullName := make([]string, 0, len(parts))
+
+	for i, p := range parts {
+		if normalized[i] == "" {
+			continue
+		}
+		fullName = append(fullName, strings.TrimSpace(p.value))
+
+		part := NamePart{
+			Part:    p.part,
+			Value:   strings.TrimSpace(p.value),
+			Letters: make([]LetterValue, 0, len(normalized[i])),
+		}
+
+		letters := make([]TraceOperand, 0, len(normalized[i]))
+		for _, r := range normalized[i] {
+			value, ok := table[r]
+			if !ok {
+				return nil, fmt.Errorf("unsupported letter %q for %s alphabet", r, alphabet)
+			}
+
+			letter := plus(string(r), value)
+			letters = append(letters, letter)
+
+			vowel := strings.ContainsRune(vowelLetters, r)
+			if vowel {
+				vowels = append(vowels, letter)
+			} else {
+				consonants = append(consonants, letter)
+			}
+
+			part.Letters = append(part.Letters, LetterValue{
+				Letter: string(r),
+				Value:  value,
+				Vowel:  vowel,
+			})
+		}
+
+		part.Sum = trace.sum(p.part+".sum", letters...)
+		part.Number = trace.reduceName(p.part+".number", reduction, plus(p.part+".sum", part.Sum))
+		total = append(total, plus(p.part+".sum", part.Sum))
+		result.Parts = append(result.Parts, part)
+	}
+
+	result.FullName = strings.Join(fullName, " ")
+	result.Expression = trace.reduceName("expression", reduction, total...)
+	result.SoulUrge = trace.reduceName("soulUrge", reduction, vowels...)
+	result.Personality = trace.reduceName("personality", reduction, consonants...)
+
+	return result, nil
+}
+
+// normalizeName приводит имя к нижнему регистру, убирает диакритику
+// и все символы, кроме букв. Ё заменяется на Е, Й - на И
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch r {
+		case 'ё':
+			r = 'е'
+		case 'й':
+			r = 'и'
+		}
+
+		// Кириллица сохраняется как есть, у латиницы убирается диакритика
+		if unicode.Is(unicode.Cyrillic, r) {
+			b.WriteRune(r)
+			continue
+		}
+
+		for _, base := range norm.NFD.String(string(r)) {
+			if unicode.IsLetter(base) {
+				b.WriteRune(base)
+			}
+		}
+	}
+	return b.String()
+}
+
+// detectAlphabet определяет алфавит по первой букве имени
+func detectAlphabet(name string) string {
+	for _, r := range name {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return AlphabetCyrillic
+		}
+		if unicode.Is(unicode.Latin, r) {
+			return AlphabetLatin
+		}
+	}
+	return AlphabetCyrillic
+}
+
+// reduceNameNumber приводит сумму к числу выбранным способом
+func reduceNameNumber(sum int, reduction string) int {
+	if reduction == ReductionArcana {
+		return reduceTo22(sum)
+	}
+
+	// Нумерология: сводим к 1-9, сохраняя мастер-числа
+	for !isNumerologyNumber(sum) {
+		digits := 0
+		for sum > 0 {
+			digits += sum % 10
+			sum /= 10
+		}
+		sum = digits
+	}
+	return sum
+}
+
+// isNumerologyNumber проверяет, что число не нужно сводить дальше
+func isNumerologyNumber(n int) bool {
+	return n <= 9 || n == 11 || n == 22 || n == 33
+}
+
+// reduceName складывает операнды, приводит сумму способом reduction
+// и записывает шаг в трассировку
+func (t *Trace) reduceName(name, reduction string, operands ...TraceOperand) int {
+	sum := operandsSum(operands)
+	value := reduceNameNumber(sum, reduction)
+	if t != nil {
+		chain, formula := reduceChain(sum), "reduce"
+		if reduction == ReductionNumerology {
+			chain, formula = digitSumChain(sum, isNumerologyNumber), "numerology"
+		}
+		t.record(TraceStep{
+			Name:       name,
+			Formula:    formula + "(" + operandsFormula(operands) + ")",
+			Operands:   operands,
+			Reduction:  chain,
+			Value:      value,
+			Expression: operandsExpression(operands) + chainExpression(chain),
+		})
+	}
+	return value
+}
@@ -2,12 +2,25 @@ package calculator
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// Режимы расчета психоматрицы
+const (
+	PythagorasModeAlexandrov = "alexandrov" // Дата + рабочие числа
+	PythagorasModeDateOnly   = "date_only"  // Только цифры даты
 )
 
 // PythagorasMatrix представляет психоматрицу Пифагора
 type PythagorasMatrix struct {
-	Cells map[int]int `json:"cells"`
-	Lines Lines       `json:"lines"`
+	Mode           string      `json:"mode"`
+	WorkingNumbers []int       `json:"workingNumbers,omitempty"`
+	Cells          map[int]int `json:"cells"`
+	Lines          Lines       `json:"lines"`
 }
 
 // Lines представляет линии психоматрицы
@@ -17,21 +30,53 @@ type Lines struct {
 	Diagonals []int `json:"diagonals"`
 }
 
-// CalculatePythagoras рассчитывает психоматрицу Пифагора по дате рождения
-func CalculatePythagoras(birthDate string) (*PythagorasMatrix, error) {
-	// Валидация формата даты
-	if err := validateDateFormat(birthDate); err != nil {
-		return nil, err
+// CalculatePythagoras рассчитывает психоматрицу Пифагора по дате рождения.
+// По умолчанию используется методика Александрова с рабочими числами
+func CalculatePythagoras(birthDate BirthDate, mode string) (*PythagorasMatrix, error) {
+	return CalculatePythagorasWithTrace(birthDate, mode, nil)
+}
+
+// CalculatePythagorasWithTrace рассчитывает психоматрицу и записывает в trace
+// рабочие числа, подсчет цифр и суммы линий
+func CalculatePythagorasWithTrace(birthDate BirthDate, mode string, trace *Trace) (*PythagorasMatrix, error) {
+	if birthDate.IsZero() {
+		return nil, ErrEmptyBirthDate
+	}
+
+	if mode == "" {
+		mode = PythagorasModeAlexandrov
+	}
+	if mode != PythagorasModeAlexandrov && mode != PythagorasModeDateOnly {
+		return nil, fmt.Errorf("unknown pythagoras mode: %s", mode)
 	}
 
 	// Извлекаем цифры из даты (без нулей)
-	digits := extractDigits(birthDate)
+	digits := extractDigits(birthDate.String())
+	source := "birthDate"
+
+	// Добавляем цифры рабочих чисел
+	var workingNumbers []int
+	if mode == PythagorasModeAlexandrov {
+		workingNumbers = calculateWorkingNumbers(birthDate, trace)
+		for _, number := range workingNumbers {
+			digits = append(digits, extractDigits(strconv.Itoa(number))...)
+		}
+		source += ", workingNumbers"
+	}
+
 	nonZeroDigits := make([]int, 0)
 	for _, d := range digits {
 		if d > 0 {
 			nonZeroDigits = append(nonZeroDigits, d)
 		}
 	}
+	trace.record(TraceStep{
+		Name:       "digits",
+		Formula:    "nonzero(digits(" + source + "))",
+		Digits:     nonZeroDigits,
+		Value:      len(nonZeroDigits),
+		Expression: joinInts(nonZeroDigits, ", "),
+	})
 
 	// Подсчет количества каждой цифры 1-9
 	cells := make(map[int]int)
@@ -44,27 +89,46 @@ func CalculatePythagoras(birthDate string) (*PythagorasMatrix, error) {
 			cells[digit]++
 		}
 	}
+	for i := 1; i <= 9; i++ {
+		matched := make([]int, cells[i])
+		for j := range matched {
+			matched[j] = i
+		}
+		trace.record(TraceStep{
+			Name:       fmt.Sprintf("cells[%d]", i),
+			Formula:    fmt.Sprintf("count(digits, %d)", i),
+			Digits:     matched,
+			Value:      cells[i],
+			Expression: strconv.Itoa(cells[i]),
+		})
+	}
+
+	cell := func(i int) TraceOperand {
+		return plus(fmt.Sprintf("cells[%d]", i), cells[i])
+	}
 
 	// Расчет линий
 	rows := []int{
-		cells[1] + cells[2] + cells[3], // Целеустремленность
-		cells[4] + cells[5] + cells[6], // Семья, стабильность
-		cells[7] + cells[8] + cells[9], // Привычки, таланты
+		trace.sum("rows[0]", cell(1), cell(2), cell(3)), // Целеустремленность
+		trace.sum("rows[1]", cell(4), cell(5), cell(6)), // Семья, стабильность
+		trace.sum("rows[2]", cell(7), cell(8), cell(9)), // Привычки, таланты
 	}
 
 	columns := []int{
-		cells[1] + cells[4] + cells[7], // Самооценка
-		cells[2] + cells[5] + cells[8], // Материальность
-		cells[3] + cells[6] + cells[9], // Талант
+		trace.sum("columns[0]", cell(1), cell(4), cell(7)), // Самооценка
+		trace.sum("columns[1]", cell(2), cell(5), cell(8)), // Материальность
+		trace.sum("columns[2]", cell(3), cell(6), cell(9)), // Талант
 	}
 
 	diagonals := []int{
-		cells[1] + cells[5] + cells[9], // Духовность
-		cells[3] + cells[5] + cells[7], // Темперамент
+		trace.sum("diagonals[0]", cell(1), cell(5), cell(9)), // Духовность
+		trace.sum("diagonals[1]", cell(3), cell(5), cell(7)), // Темперамент
 	}
 
 	return &PythagorasMatrix{
-		Cells: cells,
+		Mode:           mode,
+		WorkingNumbers: workingNumbers,
+		Cells:          cells,
 		Lines: Lines{
 			Rows:      rows,
 			Columns:   columns,
@@ -73,59 +137,161 @@ func CalculatePythagoras(birthDate string) (*PythagorasMatrix, error) {
 	}, nil
 }
 
-// InterpretPythagoras возвращает интерпретацию психоматрицы
-func InterpretPythagoras(matrix *PythagorasMatrix) map[string]string {
-	interpretations := make(map[string]string)
-
-	// Интерпретация характера (первая строка)
-	switch {
-	case matrix.Lines.Rows[0] == 0:
-		interpretations["character"] = "Отсутствие целеустремленности. Важно развивать силу воли."
-	case matrix.Lines.Rows[0] <= 2:
-		interpretations["character"] = "Слабая целеустремленность. Нужна мотивация извне."
-	case matrix.Lines.Rows[0] <= 4:
-		interpretations["character"] = "Средняя целеустремленность. Достигает целей при должных усилиях."
-	case matrix.Lines.Rows[0] <= 6:
-		interpretations["character"] = "Сильная целеустремленность. Упорно идет к своим целям."
-	default:
-		interpretations["character"] = "Очень сильная целеустремленность. Лидерские качества."
-	}
-
-	// Интерпретация семейности (вторая строка)
-	switch {
-	case matrix.Lines.Rows[1] == 0:
-		interpretations["family"] = "Слабая привязанность к семье. Важно развивать семейные ценности."
-	case matrix.Lines.Rows[1] <= 2:
-		interpretations["family"] = "Средняя семейность. Баланс между личным и семейным."
-	case matrix.Lines.Rows[1] <= 4:
-		interpretations["family"] = "Сильная семейность. Семья на первом месте."
-	default:
-		interpretations["family"] = "Очень сильная семейность. Семья - главная ценность."
-	}
-
-	// Интерпретация талантов (третья строка)
-	switch {
-	case matrix.Lines.Rows[2] == 0:
-		interpretations["talents"] = "Таланты скрыты. Требуется самопознание."
-	case matrix.Lines.Rows[2] <= 2:
-		interpretations["talents"] = "Есть таланты, но требуется развитие."
-	case matrix.Lines.Rows[2] <= 4:
-		interpretations["talents"] = "Выраженные таланты. Хорошие способности."
-	default:
-		interpretations["talents"] = "Множество талантов. Творческая натура."
-	}
-
-	// Интерпретация самооценки (первый столбец)
-	switch {
-	case matrix.Lines.Columns[0] == 0:
-		interpretations["selfEsteem"] = "Низкая самооценка. Важно работать над уверенностью."
-	case matrix.Lines.Columns[0] <= 2:
-		interpretations["selfEsteem"] = "Средняя самооценка. Периодические сомнения."
-	case matrix.Lines.Columns[0] <= 4:
-		interpretations["selfEsteem"] = "Хорошая самооценка. Уверенность в себе."
-	default:
-		interpretations["selfEsteem"] = "Очень высокая самооценка. Возможна самоуверенность."
-	}
-
-	return interpretations
+// calculateWorkingNumbers рассчитывает четыре рабочих числа по методике Александрова:
+// 1 - сумма цифр даты, 2 - сумма цифр первого числа,
+// 3 - первое число минус удвоенная первая значащая цифра дня, 4 - сумма цифр третьего числа
+func calculateWorkingNumbers(birthDate BirthDate, trace *Trace) []int {
+	first := trace.digitSum("workingNumbers[0]", "birthDate", extractDigits(birthDate.String()))
+	second := trace.digitSum("workingNumbers[1]", "workingNumbers[0]", extractDigits(strconv.Itoa(first)))
+
+	day := birthDate.Day()
+	dayDigit := day / 10
+	if dayDigit == 0 {
+		dayDigit = day
+	}
+	trace.record(TraceStep{
+		Name:       "dayDigit",
+		Formula:    "firstDigit(day)",
+		Operands:   []TraceOperand{plus("day", day)},
+		Value:      dayDigit,
+		Expression: strconv.Itoa(day) + " → " + strconv.Itoa(dayDigit),
+	})
+
+	// Для дат после 2000 года третье число может быть отрицательным,
+	// в квадрат попадают цифры его модуля
+	third := trace.sum("workingNumbers[2]", plus("workingNumbers[0]", first), minus("2 * dayDigit", 2*dayDigit))
+	if third < 0 {
+		third = trace.assign("workingNumbers[2]", plus("|workingNumbers[2]|", -third))
+	}
+	fourth := trace.digitSum("workingNumbers[3]", "workingNumbers[2]", extractDigits(strconv.Itoa(third)))
+
+	return []int{first, second, third, fourth}
+}
+
+// joinInts объединяет числа через разделитель
+func joinInts(values []int, sep string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, sep)
+}
+
+// PythagorasReport - полный результат /calculate/pythagoras: психоматрица с интерпретацией
+type PythagorasReport struct {
+	Mode            string                    `json:"mode"`
+	WorkingNumbers  []int                     `json:"workingNumbers,omitempty"`
+	Cells           map[int]int               `json:"cells"`
+	Lines           Lines                     `json:"lines"`
+	Interpretations *PythagorasInterpretation `json:"interpretations"`
+}
+
+// CalculatePythagorasReport рассчитывает психоматрицу и ее интерпретацию
+func CalculatePythagorasReport(birthDate BirthDate, mode string) (*PythagorasReport, error) {
+	return CalculatePythagorasReportWithTrace(birthDate, mode, nil)
+}
+
+// CalculatePythagorasReportWithTrace рассчитывает психоматрицу с интерпретацией и записывает шаги в trace
+func CalculatePythagorasReportWithTrace(birthDate BirthDate, mode string, trace *Trace) (*PythagorasReport, error) {
+	result, err := CalculatePythagorasWithTrace(birthDate, mode, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PythagorasReport{
+		Mode:            result.Mode,
+		WorkingNumbers:  result.WorkingNumbers,
+		Cells:           result.Cells,
+		Lines:           result.Lines,
+		Interpretations: InterpretPythagoras(result),
+	}, nil
+}
+
+// PythagorasInterpretation представляет интерпретацию психоматрицы, сгруппированную по элементам квадрата
+type PythagorasInterpretation struct {
+	Cells     []CellInterpretation `json:"cells"`
+	Rows      []LineInterpretation `json:"rows"`
+	Columns   []LineInterpretation `json:"columns"`
+	Diagonals []LineInterpretation `json:"diagonals"`
+}
+
+// CellInterpretation представляет интерпретацию ячейки (цифры 1-9)
+type CellInterpretation struct {
+	Digit int       `json:"digit"`
+	Key   string    `json:"key"`
+	Name  i18n.Text `json:"name"`
+	Count int       `json:"count"`
+	Level string    `json:"level"`
+	Text  i18n.Text `json:"text"`
+}
+
+// LineInterpretation представляет интерпретацию строки, столбца или диагонали
+type LineInterpretation struct {
+	Key   string    `json:"key"`
+	Name  i18n.Text `json:"name"`
+	Value int       `json:"value"`
+	Level string    `json:"level"`
+	Text  i18n.Text `json:"text"`
+}
+
+// InterpretPythagoras возвращает интерпретацию психоматрицы. Градации
+// ячеек и линий определяются наборами правил pythagoras_cell и pythagoras_line
+func InterpretPythagoras(matrix *PythagorasMatrix) *PythagorasInterpretation {
+	facts := rules.Facts{}
+	for digit := 1; digit <= 9; digit++ {
+		facts.Set(fmt.Sprintf("cells[%d]", digit), matrix.Cells[digit])
+	}
+	facts.SetList("rows", matrix.Lines.Rows)
+	facts.SetList("columns", matrix.Lines.Columns)
+	facts.SetList("diagonals", matrix.Lines.Diagonals)
+
+	result := &PythagorasInterpretation{
+		Cells:     make([]CellInterpretation, 0, len(cellKeys)),
+		Rows:      interpretLines(facts, PythagorasElementRow, rowKeys, matrix.Lines.Rows),
+		Columns:   interpretLines(facts, PythagorasElementColumn, columnKeys, matrix.Lines.Columns),
+		Diagonals: interpretLines(facts, PythagorasElementDiagonal, diagonalKeys, matrix.Lines.Diagonals),
+	}
+
+	for digit := 1; digit <= 9; digit++ {
+		key := cellKeys[digit-1]
+		count := matrix.Cells[digit]
+
+		facts.Set("digit", digit)
+		facts.Set("count", count)
+		level := evaluateRules(RulesPythagorasCell, facts, nil).Label("level")
+
+		result.Cells = append(result.Cells, CellInterpretation{
+			Digit: digit,
+			Key:   key,
+			Name:  pythagorasName(PythagorasElementCell, key),
+			Count: count,
+			Level: level,
+			Text:  pythagorasText(PythagorasElementCell, key, level),
+		})
+	}
+
+	return result
+}
+
+// interpretLines интерпретирует набор линий по их суммам
+func interpretLines(facts rules.Facts, element string, keys []string, values []int) []LineInterpretation {
+	lines := make([]LineInterpretation, 0, len(keys))
+	for i, key := range keys {
+		if i >= len(values) {
+			break
+		}
+
+		facts.Set("index", i)
+		facts.Set("value", values[i])
+		level := evaluateRules(RulesPythagorasLine, facts, nil).Label("level")
+
+		lines = append(lines, LineInterpretation{
+			Key:   key,
+			Name:  pythagorasName(element, key),
+			Value: values[i],
+			Level: level,
+			Text:  pythagorasText(element, key, level),
+		})
+	}
+	return lines
 }
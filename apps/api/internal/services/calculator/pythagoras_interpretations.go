@@ -0,0 +1,88 @@
+package calculator
+
+import (
+	"arcanum/internal/i18n"
+)
+
+// Элементы психоматрицы
+const (
+	PythagorasElementCell     = "cell"
+	PythagorasElementRow      = "row"
+	PythagorasElementColumn   = "column"
+	PythagorasElementDiagonal = "diagonal"
+)
+
+// Градации ячеек. Пороги задаются правилами pythagoras_cell (по умолчанию 0, 1, 2, 3, 4-6, 7+)
+var cellLevels = [6]string{"absent", "weak", "normal", "strong", "very_strong", "excess"}
+
+// Градации линий. Пороги задаются правилами pythagoras_line (по умолчанию 0, 1-2, 3-4, 5-6, 7+)
+var lineLevels = [5]string{"absent", "weak", "medium", "strong", "very_strong"}
+
+// Ключи элементов психоматрицы по порядку. Тексты хранятся в каталогах
+// сообщений под ключами pythagoras.<element>.<key>.name и pythagoras.<element>.<key>.<level>
+var (
+	cellKeys     = []string{"character", "energy", "cognition", "health", "logic", "labor", "luck", "duty", "memory"}
+	rowKeys      = []string{"character", "family", "talents"}
+	columnKeys   = []string{"selfEsteem", "material", "creativity"}
+	diagonalKeys = []string{"spirituality", "temperament"}
+)
+
+// pythagorasName возвращает локализуемое название элемента психоматрицы
+func pythagorasName(element, key string) i18n.Text {
+	return i18n.T("pythagoras." + element + "." + key + ".name")
+}
+
+// pythagorasText возвращает локализуемую интерпретацию элемента для градации
+func pythagorasText(element, key, level string) i18n.Text {
+	return i18n.T("pythagoras." + element + "." + key + "." + level)
+}
+
+// PythagorasTextSet - встроенные тексты элемента психоматрицы по градациям
+type PythagorasTextSet struct {
+	Element string
+	Key     string
+	Name    string
+	Texts   map[string]string
+}
+
+// BuiltinPythagorasTexts возвращает встроенные тексты всех ячеек, строк, столбцов
+// и диагоналей на указанном языке
+func BuiltinPythagorasTexts(lang string) []PythagorasTextSet {
+	groups := []struct {
+		element string
+		keys    []string
+	}{
+		{PythagorasElementCell, cellKeys},
+		{PythagorasElementRow, rowKeys},
+		{PythagorasElementColumn, columnKeys},
+		{PythagorasElementDiagonal, diagonalKeys},
+	}
+
+	bundle := i18n.Default()
+	sets := make([]PythagorasTextSet, 0, len(cellKeys)+len(rowKeys)+len(columnKeys)+len(diagonalKeys))
+	for _, group := range groups {
+		levels := PythagorasLevels(group.element)
+		for _, key := range group.keys {
+			texts := make(map[string]string, len(levels))
+			for _, level := range levels {
+				texts[level] = bundle.Render(lang, pythagorasText(group.element, key, level))
+			}
+			sets = append(sets, PythagorasTextSet{
+				Element: group.element,
+				Key:     key,
+				Name:    bundle.Render(lang, pythagorasName(group.element, key)),
+				Texts:   texts,
+			})
+		}
+	}
+
+	return sets
+}
+
+// PythagorasLevels возвращает градации, используемые для элемента психоматрицы
+func PythagorasLevels(element string) []string {
+	if element == PythagorasElementCell {
+		return cellLevels[:]
+	}
+	return lineLevels[:]
+}
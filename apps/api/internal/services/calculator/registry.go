@@ -0,0 +1,555 @@
+package calculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/models"
+)
+
+var (
+	ErrUnknownCalculationType = errors.New("unknown calculation type")
+	ErrUnknownVersion         = errors.New("unknown algorithm version")
+)
+
+// Calculator - версионированная реализация расчета одного типа.
+// Входные данные передаются в том же JSON формате, что и в запросах API
+type Calculator interface {
+	Type() models.CalculationType
+	Version() int
+	Calculate(input json.RawMessage) (interface{}, error)
+}
+
+// Registry хранит реализации расчетов по типу и версии алгоритма
+type Registry struct {
+	calculators map[models.CalculationType]map[int]Calculator
+}
+
+// NewRegistry создает пустой реестр
+func NewRegistry() *Registry {
+	return &Registry{
+		calculators: make(map[models.CalculationType]map[int]Calculator),
+	}
+}
+
+// Register добавляет реализацию в реестр
+func (r *Registry) Register(calc Calculator) {
+	versions, ok := r.calculators[calc.Type()]
+	if !ok {
+		versions = make(map[int]Calculator)
+		r.calculators[calc.Type()] = versions
+	}
+	versions[calc.Version()] = calc
+}
+
+// Get возвращает реализацию указанной версии. Версия 0 означает последнюю
+func (r *Registry) Get(calcType models.CalculationType, version int) (Calculator, error) {
+	versions, ok := r.calculators[calcType]
+	if !ok {
+		return nil, ErrUnknownCalculationType
+	}
+
+	if version == 0 {
+		version = r.LatestVersion(calcType)
+	}
+
+	calc, ok := versions[version]
+	if !ok {
+		return nil, ErrUnknownVersion
+	}
+	return calc, nil
+}
+
+// LatestVersion возвращает последнюю версию алгоритма для типа (0, если тип неизвестен)
+func (r *Registry) LatestVersion(calcType models.CalculationType) int {
+	latest := 0
+	for version := range r.calculators[calcType] {
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// Versions возвращает все версии алгоритма для типа по возрастанию
+func (r *Registry) Versions(calcType models.CalculationType) []int {
+	versions := make([]int, 0, len(r.calculators[calcType]))
+	for version := range r.calculators[calcType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// DefaultRegistry создает реестр со всеми встроенными расчетами.
+// Последняя версия каждого типа возвращает тот же результат, что и обработчики
+// /calculate/*. Любое изменение результата (значений или полей) требует новой версии,
+// а прежние версии должны воспроизводить сохраненные результаты. Тексты в результатах -
+// ключи сообщений i18n: их перевод и правки в CMS меняют контент, а не алгоритм
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	r.Register(calculatorFunc{models.CalculationTypeMatrix, 1, calculateMatrixV1})
+	r.Register(calculatorFunc{models.CalculationTypeMatrix, 2, calculateMatrixV2})
+	r.Register(calculatorFunc{models.CalculationTypePythagoras, 1, calculatePythagorasV1})
+	r.Register(calculatorFunc{models.CalculationTypePythagoras, 2, calculatePythagorasV2})
+	r.Register(calculatorFunc{models.CalculationTypeCompatibility, 1, calculateCompatibilityV1})
+	r.Register(calculatorFunc{models.CalculationTypeCompatibility, 2, calculateCompatibilityV2})
+	r.Register(calculatorFunc{models.CalculationTypeCompatibility, 3, calculateCompatibilityV3})
+	r.Register(calculatorFunc{models.CalculationTypeChildRole, 1, calculateChildRoleV1})
+	r.Register(calculatorFunc{models.CalculationTypeChildRole, 2, calculateChildRoleV2})
+	r.Register(calculatorFunc{models.CalculationTypeAgeTimeline, 1, calculateAgeTimelineV1})
+	r.Register(calculatorFunc{models.CalculationTypeNameNumber, 1, calculateNameNumberV1})
+	r.Register(calculatorFunc{models.CalculationTypeGroupCompatibility, 1, calculateGroupCompatibilityV1})
+	r.Register(calculatorFunc{models.CalculationTypeGroupCompatibility, 2, calculateGroupCompatibilityV2})
+	r.Register(calculatorFunc{models.CalculationTypeHealthMap, 1, calculateHealthMapV1})
+	r.Register(calculatorFunc{models.CalculationTypeForecasts, 1, calculateForecastsV1})
+	r.Register(calculatorFunc{models.CalculationTypeDay, 1, calculateDayV1})
+	r.Register(calculatorFunc{models.CalculationTypeFavorableDates, 1, calculateFavorableDatesV1})
+
+	return r
+}
+
+// calculatorFunc адаптирует функцию расчета к интерфейсу Calculator
+type calculatorFunc struct {
+	calcType models.CalculationType
+	version  int
+	fn       func(input json.RawMessage) (interface{}, error)
+}
+
+func (c calculatorFunc) Type() models.CalculationType { return c.calcType }
+
+func (c calculatorFunc) Version() int { return c.version }
+
+func (c calculatorFunc) Calculate(input json.RawMessage) (interface{}, error) {
+	return c.fn(input)
+}
+
+// Входные данные расчетов
+
+type birthDateInput struct {
+	BirthDate BirthDate `json:"birthDate"`
+	Name      string    `json:"name"`
+}
+
+type pythagorasInput struct {
+	BirthDate BirthDate `json:"birthDate"`
+	Mode      string    `json:"mode"`
+}
+
+type compatibilityInput struct {
+	Person1 birthDateInput `json:"person1"`
+	Person2 birthDateInput `json:"person2"`
+	Profile string         `json:"profile"`
+}
+
+type childRoleInput struct {
+	ChildDate     BirthDate       `json:"childDate"`
+	Parent1       birthDateInput  `json:"parent1"`
+	Parent2       birthDateInput  `json:"parent2"`
+	ExistingChild *birthDateInput `json:"existingChild"`
+}
+
+type ageTimelineInput struct {
+	BirthDate BirthDate `json:"birthDate"`
+	Age       *float64  `json:"age"`
+	Date      string    `json:"date"`
+}
+
+type nameNumberInput struct {
+	FirstName  string `json:"firstName"`
+	Patronymic string `json:"patronymic"`
+	LastName   string `json:"lastName"`
+	Alphabet   string `json:"alphabet"`
+	Reduction  string `json:"reduction"`
+}
+
+type groupCompatibilityInput struct {
+	People []birthDateInput `json:"people"`
+}
+
+type forecastInput struct {
+	BirthDate BirthDate `json:"birthDate"`
+	Year      inputInt  `json:"year"`
+	EndYear   inputInt  `json:"endYear"`
+}
+
+type dayInput struct {
+	BirthDate BirthDate `json:"birthDate"`
+	Date      string    `json:"date"`
+	Timezone  string    `json:"timezone"`
+}
+
+type favorableDatesInput struct {
+	Event        string           `json:"event"`
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Participants []birthDateInput `json:"participants"`
+	Limit        inputInt         `json:"limit"`
+}
+
+// inputInt - целое число входных данных. Принимает и строку с числом:
+// в пакетном расчете значения колонок CSV передаются строками
+type inputInt int
+
+func (v *inputInt) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = inputInt(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("expected integer, got %s", data)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("expected integer, got %q", s)
+	}
+	*v = inputInt(n)
+	return nil
+}
+
+// decodeInput разбирает входные данные расчета
+func decodeInput(input json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(input, v); err != nil {
+		return fmt.Errorf("invalid input data: %w", err)
+	}
+	return nil
+}
+
+// calculateMatrixV1 - расчет до кармических программ хвоста: в fullMatrix
+// нет поля karmicProgram
+func calculateMatrixV1(input json.RawMessage) (interface{}, error) {
+	result, err := calculateMatrixV2(input)
+	if err != nil {
+		return nil, err
+	}
+
+	report := result.(*MatrixReport)
+	report.FullMatrix = fullMatrixV1(report.FullMatrix)
+	return report, nil
+}
+
+// calculateMatrixV2 - кармическая программа хвоста в fullMatrix.karmicProgram
+func calculateMatrixV2(input json.RawMessage) (interface{}, error) {
+	var in birthDateInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculateMatrixReport(in.BirthDate)
+}
+
+// calculatePythagorasV1 - исходный расчет только по цифрам даты
+func calculatePythagorasV1(input json.RawMessage) (interface{}, error) {
+	var in pythagorasInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculatePythagorasReport(in.BirthDate, PythagorasModeDateOnly)
+}
+
+// calculatePythagorasV2 - методика Александрова с рабочими числами
+func calculatePythagorasV2(input json.RawMessage) (interface{}, error) {
+	var in pythagorasInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculatePythagorasReport(in.BirthDate, in.Mode)
+}
+
+// calculateCompatibilityV1 - расчет до профилей оценки: всегда романтический
+// профиль, в результате нет полей profile и aspectNames
+func calculateCompatibilityV1(input json.RawMessage) (interface{}, error) {
+	var in compatibilityInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	result, err := CalculateCompatibilityReport(in.Person1.BirthDate, in.Person2.BirthDate, CompatibilityProfileRomantic)
+	if err != nil {
+		return nil, err
+	}
+	return &compatibilityReportV1{
+		compatibilityResultV1: newCompatibilityResultV1(&result.CompatibilityResult),
+		CoupleMatrix:          fullMatrixV1(result.CoupleMatrix),
+	}, nil
+}
+
+// calculateCompatibilityV2 - профили оценки (romantic, business, friendship),
+// матрица пары без кармической программы хвоста
+func calculateCompatibilityV2(input json.RawMessage) (interface{}, error) {
+	result, err := calculateCompatibilityV3(input)
+	if err != nil {
+		return nil, err
+	}
+
+	report := result.(*CompatibilityReport)
+	report.CoupleMatrix = fullMatrixV1(report.CoupleMatrix)
+	return report, nil
+}
+
+// calculateCompatibilityV3 - кармическая программа хвоста в coupleMatrix.karmicProgram
+func calculateCompatibilityV3(input json.RawMessage) (interface{}, error) {
+	var in compatibilityInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculateCompatibilityReport(in.Person1.BirthDate, in.Person2.BirthDate, in.Profile)
+}
+
+// calculateChildRoleV1 - задачи с родителями по исходному списку из десяти арканов,
+// для остальных - общий текст
+func calculateChildRoleV1(input json.RawMessage) (interface{}, error) {
+	result, err := calculateChildRoleV2(input)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := &result.(*ChildRoleResult).CompatibilityWithParents
+	for _, parent := range []*ParentChildCompatibility{&parents.Parent1, &parents.Parent2} {
+		parent.Interpretation = parentChildTaskV1(parent.TaskArcana)
+	}
+	return result, nil
+}
+
+// calculateChildRoleV2 - задачи с родителями из справочника арканов
+func calculateChildRoleV2(input json.RawMessage) (interface{}, error) {
+	var in childRoleInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	var siblingDate *BirthDate
+	if in.ExistingChild != nil {
+		siblingDate = &in.ExistingChild.BirthDate
+	}
+	return CalculateChildRole(in.ChildDate, in.Parent1.BirthDate, in.Parent2.BirthDate, siblingDate)
+}
+
+func calculateAgeTimelineV1(input json.RawMessage) (interface{}, error) {
+	var in ageTimelineInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculateAgeTimelineReport(in.BirthDate, in.Age, in.Date)
+}
+
+func calculateNameNumberV1(input json.RawMessage) (interface{}, error) {
+	var in nameNumberInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculateNameNumber(in.FirstName, in.Patronymic, in.LastName, in.Alphabet, in.Reduction)
+}
+
+func calculateHealthMapV1(input json.RawMessage) (interface{}, error) {
+	var in birthDateInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	return CalculateHealthMap(in.BirthDate)
+}
+
+// calculateForecastsV1 - прогноз на год year или на годы year-endYear
+func calculateForecastsV1(input json.RawMessage) (interface{}, error) {
+	var in forecastInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	endYear := in.Year
+	if in.EndYear != 0 {
+		endYear = in.EndYear
+	}
+	return CalculateForecasts(in.BirthDate, int(in.Year), int(endYear))
+}
+
+// MultiYearForecast проверяет, что входные данные forecasts запрашивают прогноз
+// больше чем на один год. Некорректные данные отклоняет сам расчет
+func MultiYearForecast(input json.RawMessage) bool {
+	var in forecastInput
+	if err := decodeInput(input, &in); err != nil {
+		return false
+	}
+	return in.EndYear != 0 && in.EndYear != in.Year
+}
+
+// calculateDayV1 - энергия дня date. В отличие от /calculate/day дата
+// обязательна: расчет на текущий день нельзя воспроизвести при пересчете
+func calculateDayV1(input json.RawMessage) (interface{}, error) {
+	var in dayInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	if in.Date == "" {
+		return nil, fmt.Errorf("date is required")
+	}
+	date, err := ParseDate(in.Date)
+	if err != nil {
+		return nil, err
+	}
+	return CalculateDayEnergyReport(in.BirthDate, date, in.Timezone)
+}
+
+func calculateFavorableDatesV1(input json.RawMessage) (interface{}, error) {
+	var in favorableDatesInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	from, err := ParseDate(in.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ParseDate(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]GroupMember, 0, len(in.Participants))
+	for i, person := range in.Participants {
+		matrix, err := CalculateMatrixFate(person.BirthDate)
+		if err != nil {
+			return nil, fmt.Errorf("participant %d: %w", i+1, err)
+		}
+		participants = append(participants, GroupMember{Name: person.Name, Matrix: matrix})
+	}
+	return CalculateFavorableDates(in.Event, participants, from, to, int(in.Limit))
+}
+
+// calculateGroupCompatibilityV1 - результаты пар в формате compatibility v1
+func calculateGroupCompatibilityV1(input json.RawMessage) (interface{}, error) {
+	result, err := calculateGroupCompatibilityV2(input)
+	if err != nil {
+		return nil, err
+	}
+	return newGroupCompatibilityResultV1(result.(*GroupCompatibilityResult)), nil
+}
+
+// calculateGroupCompatibilityV2 - результаты пар с профилем и названиями аспектов
+func calculateGroupCompatibilityV2(input json.RawMessage) (interface{}, error) {
+	var in groupCompatibilityInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	members := make([]GroupMember, 0, len(in.People))
+	for i, person := range in.People {
+		matrix, err := CalculateMatrixFate(person.BirthDate)
+		if err != nil {
+			return nil, fmt.Errorf("person %d: %w", i+1, err)
+		}
+		members = append(members, GroupMember{Name: person.Name, Matrix: matrix})
+	}
+	return CalculateGroupCompatibility(members)
+}
+
+// childTasksV1 - арканы с собственным текстом задачи в child_role v1
+var childTasksV1 = map[int]bool{7: true, 8: true, 9: true, 11: true, 13: true, 16: true, 17: true, 18: true, 19: true, 20: true}
+
+// parentChildTaskV1 возвращает текст задачи ребенка с родителем в child_role v1
+func parentChildTaskV1(number int) i18n.Text {
+	if childTasksV1[number] {
+		return i18n.T("child.task.v1." + strconv.Itoa(number))
+	}
+	return i18n.T("child.task.v1.default", i18n.Params{"arcana": GetArcanaName(number)})
+}
+
+// Результаты прежних версий. Поля, добавленные в следующих версиях, в них
+// не попадают, чтобы пересчет совпадал с сохраненными результатами
+
+// compatibilityResultV1 - результат совместимости пары в compatibility v1
+type compatibilityResultV1 struct {
+	OverallScore    int         `json:"overallScore"`
+	KarmicTask      int         `json:"karmicTask"`
+	DestinyArcana   int         `json:"destinyArcana"`
+	Aspects         Aspects     `json:"aspects"`
+	Strengths       []i18n.Text `json:"strengths"`
+	Challenges      []i18n.Text `json:"challenges"`
+	Recommendations []i18n.Text `json:"recommendations"`
+	ArcanaNames     ArcanaNames `json:"arcanaNames"`
+}
+
+func newCompatibilityResultV1(r *CompatibilityResult) *compatibilityResultV1 {
+	return &compatibilityResultV1{
+		OverallScore:    r.OverallScore,
+		KarmicTask:      r.KarmicTask,
+		DestinyArcana:   r.DestinyArcana,
+		Aspects:         r.Aspects,
+		Strengths:       r.Strengths,
+		Challenges:      r.Challenges,
+		Recommendations: r.Recommendations,
+		ArcanaNames:     r.ArcanaNames,
+	}
+}
+
+// fullMatrixV1 - полная матрица в matrix v1 и compatibility v1-v2:
+// без кармической программы хвоста
+func fullMatrixV1(m *FullMatrix) *FullMatrix {
+	if m == nil {
+		return nil
+	}
+	v1 := *m
+	v1.KarmicProgram = nil
+	return &v1
+}
+
+// compatibilityReportV1 - результат compatibility v1
+type compatibilityReportV1 struct {
+	*compatibilityResultV1
+	CoupleMatrix *FullMatrix `json:"coupleMatrix"`
+}
+
+// groupCompatibilityResultV1 - результат group_compatibility v1
+type groupCompatibilityResultV1 struct {
+	Members         []i18n.Text           `json:"members"`
+	ScoreMatrix     [][]int               `json:"scoreMatrix"`
+	Pairs           []pairCompatibilityV1 `json:"pairs"`
+	AverageScore    int                   `json:"averageScore"`
+	GroupArcana     int                   `json:"groupArcana"`
+	GroupArcanaName i18n.Text             `json:"groupArcanaName"`
+	MostHarmonious  pairCompatibilityV1   `json:"mostHarmonious"`
+	LeastHarmonious pairCompatibilityV1   `json:"leastHarmonious"`
+	Strengths       []i18n.Text           `json:"strengths"`
+	Challenges      []i18n.Text           `json:"challenges"`
+}
+
+type pairCompatibilityV1 struct {
+	Person1 int                    `json:"person1"`
+	Person2 int                    `json:"person2"`
+	Result  *compatibilityResultV1 `json:"result"`
+}
+
+func newPairCompatibilityV1(p PairCompatibility) pairCompatibilityV1 {
+	return pairCompatibilityV1{
+		Person1: p.Person1,
+		Person2: p.Person2,
+		Result:  newCompatibilityResultV1(&p.Result),
+	}
+}
+
+func newGroupCompatibilityResultV1(r *GroupCompatibilityResult) *groupCompatibilityResultV1 {
+	pairs := make([]pairCompatibilityV1, len(r.Pairs))
+	for i, pair := range r.Pairs {
+		pairs[i] = newPairCompatibilityV1(pair)
+	}
+
+	return &groupCompatibilityResultV1{
+		Members:         r.Members,
+		ScoreMatrix:     r.ScoreMatrix,
+		Pairs:           pairs,
+		AverageScore:    r.AverageScore,
+		GroupArcana:     r.GroupArcana,
+		GroupArcanaName: r.GroupArcanaName,
+		MostHarmonious:  newPairCompatibilityV1(r.MostHarmonious),
+		LeastHarmonious: newPairCompatibilityV1(r.LeastHarmonious),
+		Strengths:       r.Strengths,
+		Challenges:      r.Challenges,
+	}
+}
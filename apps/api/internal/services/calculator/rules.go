@@ -0,0 +1,215 @@
+package calculator
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"arcanum/internal/i18n"
+	"arcanum/internal/services/rules"
+)
+
+// Наборы правил интерпретации
+const (
+	RulesCompatibility           = "compatibility"
+	RulesCompatibilityBusiness   = "compatibility_business"
+	RulesCompatibilityFriendship = "compatibility_friendship"
+	RulesFavorableWedding        = "favorable_wedding"
+	RulesFavorableLaunch         = "favorable_launch"
+	RulesFavorableContract       = "favorable_contract"
+	RulesGroup                   = "group"
+	RulesGroupPair               = "group_pair"
+	RulesChildRole               = "child_role"
+	RulesParentChild             = "parent_child"
+	RulesHealth                  = "health"
+	RulesKarmicTail              = "karmic_tail"
+	RulesPythagorasCell          = "pythagoras_cell"
+	RulesPythagorasLine          = "pythagoras_line"
+)
+
+//go:embed rules/*.json
+var ruleFiles embed.FS
+
+// matrixFacts - факты одной матрицы судьбы в наборах правил для нескольких людей
+func matrixFacts(prefix string) []string {
+	return []string{prefix + ".main", prefix + ".spiritual", prefix + ".social", prefix + ".tail"}
+}
+
+// pythagorasFacts - факты психоматрицы, доступные правилам ячеек и линий
+func pythagorasFacts() []string {
+	facts := make([]string, 0, 17)
+	for digit := 1; digit <= 9; digit++ {
+		facts = append(facts, fmt.Sprintf("cells[%d]", digit))
+	}
+	for i := range rowKeys {
+		facts = append(facts, fmt.Sprintf("rows[%d]", i))
+	}
+	for i := range columnKeys {
+		facts = append(facts, fmt.Sprintf("columns[%d]", i))
+	}
+	for i := range diagonalKeys {
+		facts = append(facts, fmt.Sprintf("diagonals[%d]", i))
+	}
+	return facts
+}
+
+// compatibilitySpec - общий контракт наборов правил профилей совместимости
+var compatibilitySpec = rules.Spec{
+	Facts: append(append([]string{"karmicTask", "destinyArcana", "spiritualDiff", "mainDiff", "socialDiff"},
+		matrixFacts("person1")...), matrixFacts("person2")...),
+	Numbers: []string{
+		"spiritual", "emotional", "material", "karmic",
+		"spiritualWeight", "emotionalWeight", "materialWeight", "karmicWeight",
+		"favorableTask", "taskBonus",
+	},
+	Texts: []string{"strengths", "challenges", "recommendations"},
+}
+
+// favorableDatesSpec - общий контракт наборов правил подбора дат для событий
+var favorableDatesSpec = rules.Spec{
+	Facts: append(append([]string{"dayArcana", "participants", "person1.arcana", "person2.arcana", "pairArcana"},
+		matrixFacts("person1")...), matrixFacts("person2")...),
+	Params: []string{"person1Name", "person2Name"},
+	Numbers: []string{
+		"excluded",
+		"dayScore", "person1Score", "person2Score", "pairScore",
+		"dayWeight", "person1Weight", "person2Weight", "pairWeight",
+	},
+	Texts: []string{"reasons"},
+}
+
+// ruleSpecs - контракты наборов правил с калькуляторами
+var ruleSpecs = map[string]rules.Spec{
+	RulesCompatibility:           compatibilitySpec,
+	RulesCompatibilityBusiness:   compatibilitySpec,
+	RulesCompatibilityFriendship: compatibilitySpec,
+	RulesFavorableWedding:        favorableDatesSpec,
+	RulesFavorableLaunch:         favorableDatesSpec,
+	RulesFavorableContract:       favorableDatesSpec,
+	RulesGroupPair: {
+		Facts:   []string{"score", "karmicTask"},
+		Numbers: []string{"favorable", "tower"},
+	},
+	RulesGroup: {
+		Facts:  []string{"size", "pairs", "averageScore", "scoreSpread", "favorablePairs", "favorableShare", "towerPairs", "groupArcana"},
+		Params: []string{"leastPerson1", "leastPerson2"},
+		Texts:  []string{"strengths", "challenges"},
+	},
+	RulesChildRole: {
+		Facts:   append(append(matrixFacts("child"), matrixFacts("parent1")...), matrixFacts("parent2")...),
+		Numbers: []string{"cleanser", "healer", "karmicMirror"},
+		Labels: map[string][]string{
+			"primaryRole": {"new_impulse", "cleanser", "healer", "karmic_mirror", "fool"},
+		},
+	},
+	RulesParentChild: {
+		Facts:   []string{"taskArcana"},
+		Numbers: []string{"connectionStrength"},
+	},
+	RulesHealth: {
+		Facts:   []string{"physical", "energy", "emotions"},
+		Numbers: []string{"overloaded"},
+	},
+	RulesKarmicTail: {
+		Facts:  []string{PointTailInner, PointTailOuter, PointBottom},
+		Labels: map[string][]string{"program": karmicPrograms[:]},
+	},
+	RulesPythagorasCell: {
+		Facts:  append([]string{"digit", "count"}, pythagorasFacts()...),
+		Labels: map[string][]string{"level": cellLevels[:]},
+	},
+	RulesPythagorasLine: {
+		Facts:  append([]string{"index", "value"}, pythagorasFacts()...),
+		Labels: map[string][]string{"level": lineLevels[:]},
+	},
+}
+
+// ruleSets - действующие наборы правил. Задаются один раз при старте
+var ruleSets = mustLoadRules()
+
+// RuleSetNames возвращает имена наборов правил по алфавиту
+func RuleSetNames() []string {
+	names := make([]string, 0, len(ruleSpecs))
+	for name := range ruleSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RuleSet возвращает действующий набор правил по имени
+func RuleSet(name string) (*rules.RuleSet, bool) {
+	set, ok := ruleSets[name]
+	return set, ok
+}
+
+// ParseRules проверяет файл правил по контракту набора name
+func ParseRules(name string, data []byte) (*rules.RuleSet, error) {
+	spec, ok := ruleSpecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rule set %q", name)
+	}
+	return rules.Load(name, data, spec)
+}
+
+// LoadRules загружает наборы правил из каталога dir (файлы <name>.json).
+// Наборы, для которых нет файла, остаются встроенными. Если хотя бы один
+// файл не прошел проверку, действующие правила не меняются
+func LoadRules(dir string) error {
+	loaded := make(map[string]*rules.RuleSet, len(ruleSets))
+	for name, set := range ruleSets {
+		loaded[name] = set
+	}
+
+	for _, name := range RuleSetNames() {
+		data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read rules: %w", err)
+		}
+
+		set, err := ParseRules(name, data)
+		if err != nil {
+			return err
+		}
+		loaded[name] = set
+	}
+
+	ruleSets = loaded
+	return nil
+}
+
+// BuiltinRules возвращает встроенный файл набора правил
+func BuiltinRules(name string) ([]byte, error) {
+	if _, ok := ruleSpecs[name]; !ok {
+		return nil, fmt.Errorf("unknown rule set %q", name)
+	}
+	return ruleFiles.ReadFile("rules/" + name + ".json")
+}
+
+// mustLoadRules загружает встроенные наборы правил
+func mustLoadRules() map[string]*rules.RuleSet {
+	sets := make(map[string]*rules.RuleSet, len(ruleSpecs))
+	for _, name := range RuleSetNames() {
+		data, err := BuiltinRules(name)
+		if err != nil {
+			panic(fmt.Sprintf("calculator: %v", err))
+		}
+		set, err := ParseRules(name, data)
+		if err != nil {
+			panic(fmt.Sprintf("calculator: invalid builtin rules: %v", err))
+		}
+		sets[name] = set
+	}
+	return sets
+}
+
+// evaluateRules вычисляет действующий набор правил
+func evaluateRules(name string, facts rules.Facts, params i18n.Params) *rules.Result {
+	return ruleSets[name].Evaluate(facts, params)
+}
@@ -0,0 +1,305 @@
+package calculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/services/rules"
+)
+
+// Trace - пошаговая трассировка расчета для режима объяснения. Шаги
+// записываются теми же вызовами, которые вычисляют результат, поэтому
+// трассировка всегда совпадает с ответом. Нулевой *Trace безопасен:
+// расчеты без объяснения передают nil
+type Trace struct {
+	prefix string
+	log    *traceLog
+}
+
+type traceLog struct {
+	steps []TraceStep
+}
+
+// TraceStep - один шаг расчета
+type TraceStep struct {
+	// Name - что вычислено (main, A, cells[1], person1.tail)
+	Name string `json:"name"`
+	// Formula - формула шага в обозначениях ответа
+	Formula string `json:"formula"`
+	// Digits - цифры, извлеченные из исходного значения
+	Digits []int `json:"digits,omitempty"`
+	// Operands - значения, подставленные в формулу
+	Operands []TraceOperand `json:"operands,omitempty"`
+	// Reduction - цепочка сумм цифр от исходной суммы до результата
+	Reduction []int `json:"reduction,omitempty"`
+	// Rule - условие сработавшего правила (default - значение по умолчанию)
+	Rule string `json:"rule,omitempty"`
+	// Value - результат шага
+	Value interface{} `json:"value"`
+	// Expression - шаг с подставленными числами
+	Expression string `json:"expression"`
+}
+
+// TraceOperand - значение, подставленное в формулу
+type TraceOperand struct {
+	Name     string  `json:"name"`
+	Value    float64 `json:"value"`
+	Subtract bool    `json:"subtract,omitempty"`
+}
+
+// NewTrace создает пустую трассировку
+func NewTrace() *Trace {
+	return &Trace{log: &traceLog{}}
+}
+
+// Scope возвращает трассировку, которая пишет в тот же журнал
+// с префиксом name в именах шагов
+func (t *Trace) Scope(name string) *Trace {
+	if t == nil {
+		return nil
+	}
+	return &Trace{prefix: t.prefix + name + ".", log: t.log}
+}
+
+// Steps возвращает записанные шаги по порядку
+func (t *Trace) Steps() []TraceStep {
+	if t == nil {
+		return nil
+	}
+	return t.log.steps
+}
+
+// MarshalJSON сериализует трассировку как {"steps": [...]}
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	steps := t.Steps()
+	if steps == nil {
+		steps = []TraceStep{}
+	}
+	return json.Marshal(struct {
+		Steps []TraceStep `json:"steps"`
+	}{steps})
+}
+
+// record добавляет шаг с префиксом области
+func (t *Trace) record(step TraceStep) {
+	if t == nil {
+		return
+	}
+	step.Name = t.prefix + step.Name
+	t.log.steps = append(t.log.steps, step)
+}
+
+func plus(name string, value int) TraceOperand {
+	return TraceOperand{Name: name, Value: float64(value)}
+}
+
+func minus(name string, value int) TraceOperand {
+	return TraceOperand{Name: name, Value: float64(value), Subtract: true}
+}
+
+// operandsSum складывает операнды с учетом знака
+func operandsSum(operands []TraceOperand) int {
+	sum := 0
+	for _, o := range operands {
+		if o.Subtract {
+			sum -= int(o.Value)
+		} else {
+			sum += int(o.Value)
+		}
+	}
+	return sum
+}
+
+// reduceSum складывает операнды, приводит сумму к 1-22 и записывает шаг
+func (t *Trace) reduceSum(name string, operands ...TraceOperand) int {
+	sum := operandsSum(operands)
+	value := reduceTo22(sum)
+	if t != nil {
+		chain := reduceChain(sum)
+		t.record(TraceStep{
+			Name:       name,
+			Formula:    "reduce(" + operandsFormula(operands) + ")",
+			Operands:   operands,
+			Reduction:  chain,
+			Value:      value,
+			Expression: operandsExpression(operands) + chainExpression(chain),
+		})
+	}
+	return value
+}
+
+// sum складывает операнды без приведения и записывает шаг
+func (t *Trace) sum(name string, operands ...TraceOperand) int {
+	sum := operandsSum(operands)
+	if t != nil {
+		t.record(TraceStep{
+			Name:       name,
+			Formula:    operandsFormula(operands),
+			Operands:   operands,
+			Value:      sum,
+			Expression: operandsExpression(operands),
+		})
+	}
+	return sum
+}
+
+// digitSum складывает цифры значения source и записывает шаг
+func (t *Trace) digitSum(name, source string, digits []int) int {
+	sum := sumDigits(digits)
+	if t != nil {
+		t.record(TraceStep{
+			Name:       name,
+			Formula:    "sum(digits(" + source + "))",
+			Digits:     digits,
+			Value:      sum,
+			Expression: joinInts(digits, " + ") + " = " + strconv.Itoa(sum),
+		})
+	}
+	return sum
+}
+
+// assign записывает шаг, в котором значение берется из другого без изменений
+func (t *Trace) assign(name string, from TraceOperand) int {
+	t.record(TraceStep{
+		Name:       name,
+		Formula:    from.Name,
+		Operands:   []TraceOperand{from},
+		Value:      int(from.Value),
+		Expression: formatTraceNumber(from.Value),
+	})
+	return int(from.Value)
+}
+
+// absDiff возвращает модуль разности двух арканов и записывает шаг в трассировку
+func (t *Trace) absDiff(name string, a, b TraceOperand) int {
+	diff := int(a.Value) - int(b.Value)
+	if diff < 0 {
+		diff = -diff
+	}
+	t.record(TraceStep{
+		Name:       name,
+		Formula:    "|" + a.Name + " - " + b.Name + "|",
+		Operands:   []TraceOperand{a, b},
+		Value:      diff,
+		Expression: fmt.Sprintf("|%s - %s| = %d", formatTraceNumber(a.Value), formatTraceNumber(b.Value), diff),
+	})
+	return diff
+}
+
+// rule записывает значение, выбранное набором правил set
+func (t *Trace) rule(name, set, value string, result *rules.Result) {
+	if t == nil {
+		return
+	}
+
+	match := result.Match(value)
+	names := make([]string, 0, len(match.Facts))
+	for n := range match.Facts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	operands := make([]TraceOperand, 0, len(names))
+	for _, n := range names {
+		operands = append(operands, TraceOperand{Name: n, Value: match.Facts[n]})
+	}
+
+	condition := match.Condition
+	if condition == "" {
+		condition = "default"
+	}
+
+	var v interface{} = result.Int(value)
+	if label := result.Label(value); label != "" {
+		v = label
+	}
+
+	t.record(TraceStep{
+		Name:       name,
+		Formula:    "rules(" + set + ")." + value,
+		Operands:   operands,
+		Rule:       condition,
+		Value:      v,
+		Expression: condition + " → " + fmt.Sprint(v),
+	})
+}
+
+// reduceChain возвращает цепочку сумм цифр, которую проходит reduceTo22.
+// Для чисел 1-22 приведение не требуется
+func reduceChain(sum int) []int {
+	if sum >= 1 && sum <= 22 {
+		return nil
+	}
+	if sum <= 0 {
+		return []int{sum, 0}
+	}
+	return digitSumChain(sum, func(n int) bool { return n <= 22 })
+}
+
+// digitSumChain суммирует цифры числа, пока done не вернет true
+func digitSumChain(n int, done func(int) bool) []int {
+	if done(n) {
+		return nil
+	}
+	chain := []int{n}
+	for !done(n) {
+		n = sumDigits(extractDigits(strconv.Itoa(n)))
+		chain = append(chain, n)
+	}
+	return chain
+}
+
+// operandsFormula записывает формулу операндов по именам: A + E, total - day
+func operandsFormula(operands []TraceOperand) string {
+	var b strings.Builder
+	for i, o := range operands {
+		switch {
+		case o.Subtract:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		}
+		b.WriteString(o.Name)
+	}
+	return b.String()
+}
+
+// operandsExpression записывает формулу с числами: 5 + 1990 = 1995
+func operandsExpression(operands []TraceOperand) string {
+	var b strings.Builder
+	for i, o := range operands {
+		switch {
+		case o.Subtract:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		}
+		b.WriteString(formatTraceNumber(o.Value))
+	}
+	if len(operands) > 1 {
+		b.WriteString(" = " + strconv.Itoa(operandsSum(operands)))
+	}
+	return b.String()
+}
+
+// chainExpression записывает приведение: → 1+9+9+5 = 24 → 2+4 = 6
+func chainExpression(chain []int) string {
+	var b strings.Builder
+	for i := 1; i < len(chain); i++ {
+		prev := chain[i-1]
+		if prev <= 0 {
+			b.WriteString(" → " + strconv.Itoa(chain[i]))
+			continue
+		}
+		digits := extractDigits(strconv.Itoa(prev))
+		b.WriteString(" → " + joinInts(digits, "+") + " = " + strconv.Itoa(chain[i]))
+	}
+	return b.String()
+}
+
+func formatTraceNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
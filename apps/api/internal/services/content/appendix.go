@@ -0,0 +1,123 @@
+package content
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/models"
+	"arcanum/internal/services/arcana"
+)
+
+// AppendixLanguage - язык текстов Приложения C
+const AppendixLanguage = "ru"
+
+var (
+	arcanaHeading  = regexp.MustCompile(`^### Аркан (\d+)`)
+	sectionHeading = regexp.MustCompile(`^\*\*(.+?):\*\*\s*(.*)$`)
+)
+
+// ParseAppendix разбирает раздел "Полное описание 22 арканов" из
+// docs/APPENDIX_C_Full_Interpretations.md. Названия и аспект детей берутся
+// из справочника арканов, так как в приложении их нет в нужном виде
+func ParseAppendix(r io.Reader) ([]*models.ArcanaInterpretation, error) {
+	items := make([]*models.ArcanaInterpretation, 0, arcana.Count)
+
+	var current *models.ArcanaInterpretation
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Раздел арканов заканчивается на следующем заголовке второго уровня
+		if strings.HasPrefix(line, "## ") && len(items) > 0 {
+			break
+		}
+
+		if m := arcanaHeading.FindStringSubmatch(line); m != nil {
+			number, _ := strconv.Atoi(m[1])
+			a, ok := arcana.Get(number)
+			if !ok {
+				return nil, fmt.Errorf("unknown arcana in appendix: %d", number)
+			}
+
+			current = &models.ArcanaInterpretation{
+				ArcanaNumber: number,
+				Language:     AppendixLanguage,
+				Title:        a.Title(AppendixLanguage),
+				Child:        a.ChildTask(AppendixLanguage),
+			}
+			items = append(items, current)
+			section = ""
+			continue
+		}
+
+		if current == nil || line == "" || line == "---" {
+			continue
+		}
+
+		if m := sectionHeading.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			if m[2] != "" {
+				appendText(current, section, m[2])
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "- ") {
+			appendItem(current, section, strings.TrimSpace(line[2:]))
+			continue
+		}
+
+		appendText(current, section, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) != arcana.Count {
+		return nil, fmt.Errorf("expected %d arcanas in appendix, found %d", arcana.Count, len(items))
+	}
+
+	return items, nil
+}
+
+// appendItem добавляет пункт списка в соответствующий раздел
+func appendItem(item *models.ArcanaInterpretation, section, text string) {
+	switch section {
+	case "Позитивное проявление":
+		item.Plus = append(item.Plus, text)
+	case "Негативное проявление":
+		item.Minus = append(item.Minus, text)
+	case "Рекомендации для проработки":
+		item.Recommendations = append(item.Recommendations, text)
+	default:
+		appendText(item, section, text)
+	}
+}
+
+// appendText добавляет абзац в текстовый раздел
+func appendText(item *models.ArcanaInterpretation, section, text string) {
+	var target *string
+	switch section {
+	case "Ключевые слова":
+		target = &item.ShortDescription
+	case "В отношениях":
+		target = &item.Love
+	case "Финансы":
+		target = &item.Money
+	case "Здоровье":
+		target = &item.Health
+	default:
+		target = &item.FullDescription
+	}
+
+	if *target != "" {
+		*target += "\n"
+	}
+	*target += text
+}
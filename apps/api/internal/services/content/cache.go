@@ -0,0 +1,84 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"arcanum/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// cacheVersionKey хранит текущее поколение кэша контента
+	cacheVersionKey = "content:version"
+
+	// cacheTTL - время жизни закэшированного ответа
+	cacheTTL = time.Hour
+)
+
+// Cache кэширует готовые ответы Content API в Redis.
+// Ключи включают номер поколения, поэтому Invalidate сбрасывает весь кэш
+// одним инкрементом без перебора ключей
+type Cache struct {
+	redis *database.RedisClient
+}
+
+func NewCache(redis *database.RedisClient) *Cache {
+	return &Cache{redis: redis}
+}
+
+// Entry - закэшированный ответ: тело и фактический язык контента
+type Entry struct {
+	Language string `json:"language"`
+	Body     []byte `json:"body"`
+}
+
+// Get возвращает закэшированный ответ. Ошибки Redis считаются промахом кэша
+func (c *Cache) Get(ctx context.Context, key string) (*Entry, bool) {
+	value, err := c.redis.Get(ctx, c.versionedKey(ctx, key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Set сохраняет ответ в кэш текущего поколения
+func (c *Cache) Set(ctx context.Context, key string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return c.redis.Set(ctx, c.versionedKey(ctx, key), data, cacheTTL)
+}
+
+// Invalidate сбрасывает все закэшированные ответы (вызывается при публикации)
+func (c *Cache) Invalidate(ctx context.Context) error {
+	_, err := c.redis.Incr(ctx, cacheVersionKey)
+	return err
+}
+
+// Version возвращает текущее поколение кэша. Поколение меняется при каждой публикации
+func (c *Cache) Version(ctx context.Context) (string, error) {
+	version, err := c.redis.Get(ctx, cacheVersionKey)
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return version, err
+}
+
+func (c *Cache) versionedKey(ctx context.Context, key string) string {
+	version, err := c.redis.Get(ctx, cacheVersionKey)
+	if err != nil {
+		version = "0"
+	}
+	return fmt.Sprintf("content:v%s:%s", version, key)
+}
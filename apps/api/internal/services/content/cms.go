@@ -0,0 +1,283 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+	"arcanum/internal/models"
+	"arcanum/internal/services/arcana"
+	"arcanum/internal/services/calculator"
+)
+
+// ErrInvalidContent возвращается, если ключ, язык или данные ревизии некорректны
+var ErrInvalidContent = errors.New("invalid content")
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2})?$`)
+
+// placeholderPattern находит параметры сообщения: {arcana}, {name}
+var placeholderPattern = regexp.MustCompile(`\{[A-Za-z0-9_]+\}`)
+
+// CompatibilityTextPrefix - префикс ключей каталога сообщений с текстами совместимости
+const CompatibilityTextPrefix = "compatibility."
+
+// CMS управляет редактированием справочного контента:
+// черновик -> на проверке -> опубликовано, с историей ревизий и откатом
+type CMS struct {
+	revisions *database.ContentRevisionRepository
+	cache     *Cache
+}
+
+func NewCMS(revisions *database.ContentRevisionRepository, cache *Cache) *CMS {
+	return &CMS{
+		revisions: revisions,
+		cache:     cache,
+	}
+}
+
+// SaveDraft сохраняет новую ревизию-черновик. Каждое сохранение создает
+// отдельную ревизию, поэтому история правок не теряется
+func (s *CMS) SaveDraft(ctx context.Context, contentType models.ContentType, key, language string, data json.RawMessage, comment, authorID string) (*models.ContentRevision, error) {
+	normalized, err := normalizeContent(contentType, key, language, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &models.ContentRevision{
+		ContentType: contentType,
+		ContentKey:  key,
+		Language:    language,
+		Status:      models.ContentStatusDraft,
+		Data:        normalized,
+		Comment:     comment,
+		AuthorID:    &authorID,
+	}
+	if err := s.revisions.Create(ctx, rev); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// Submit отправляет черновик на проверку
+func (s *CMS) Submit(ctx context.Context, id string) (*models.ContentRevision, error) {
+	if err := s.revisions.Submit(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.revisions.FindByID(ctx, id)
+}
+
+// Reject возвращает ревизию с проверки в черновики
+func (s *CMS) Reject(ctx context.Context, id string) (*models.ContentRevision, error) {
+	if err := s.revisions.Reject(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.revisions.FindByID(ctx, id)
+}
+
+// Publish публикует проверенную ревизию и сбрасывает кэш Content API
+func (s *CMS) Publish(ctx context.Context, id, publisherID string) (*models.ContentRevision, error) {
+	rev, err := s.revisions.Publish(ctx, id, publisherID, models.ContentStatusReview)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Invalidate(ctx); err != nil {
+		return nil, fmt.Errorf("content published but cache invalidation failed: %w", err)
+	}
+	return rev, nil
+}
+
+// Rollback публикует содержимое указанной ревизии как новую ревизию.
+// История не переписывается: откат сам становится ревизией с автором и временем.
+// Откатиться можно только к ревизии, которая уже проходила проверку и была
+// опубликована (опубликованной или архивной), поэтому откат не обходит проверку
+func (s *CMS) Rollback(ctx context.Context, id, userID string) (*models.ContentRevision, error) {
+	target, err := s.revisions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != models.ContentStatusPublished && target.Status != models.ContentStatusArchived {
+		return nil, fmt.Errorf("%w: only published or archived revisions can be restored", database.ErrInvalidRevisionStatus)
+	}
+
+	rev := &models.ContentRevision{
+		ContentType: target.ContentType,
+		ContentKey:  target.ContentKey,
+		Language:    target.Language,
+		Status:      models.ContentStatusDraft,
+		Data:        target.Data,
+		Comment:     fmt.Sprintf("Rollback to revision %d", target.Revision),
+		AuthorID:    &userID,
+	}
+	if err := s.revisions.Create(ctx, rev); err != nil {
+		return nil, err
+	}
+
+	published, err := s.revisions.Publish(ctx, rev.ID, userID, models.ContentStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Invalidate(ctx); err != nil {
+		return nil, fmt.Errorf("content published but cache invalidation failed: %w", err)
+	}
+	return published, nil
+}
+
+// History возвращает все ревизии единицы контента, начиная с последней
+func (s *CMS) History(ctx context.Context, contentType models.ContentType, key, language string) ([]*models.ContentRevision, error) {
+	if _, err := normalizeContent(contentType, key, language, nil); err != nil {
+		return nil, err
+	}
+	return s.revisions.ListByContent(ctx, contentType, key, language)
+}
+
+// Revision возвращает ревизию по ID
+func (s *CMS) Revision(ctx context.Context, id string) (*models.ContentRevision, error) {
+	return s.revisions.FindByID(ctx, id)
+}
+
+// ReviewQueue возвращает ревизии, ожидающие проверки
+func (s *CMS) ReviewQueue(ctx context.Context) ([]*models.ContentRevision, error) {
+	return s.revisions.ListByStatus(ctx, models.ContentStatusReview)
+}
+
+// normalizeContent проверяет ключ, язык и данные ревизии и приводит данные к
+// каноническому виду. При data == nil проверяются только ключ и язык
+func normalizeContent(contentType models.ContentType, key, language string, data json.RawMessage) (json.RawMessage, error) {
+	if !languagePattern.MatchString(language) {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidContent, language)
+	}
+
+	switch contentType {
+	case models.ContentTypeArcana:
+		return normalizeArcana(key, language, data)
+	case models.ContentTypePythagoras:
+		return normalizePythagoras(key, language, data)
+	case models.ContentTypeCompatibility:
+		return normalizeCompatibility(key, language, data)
+	default:
+		return nil, fmt.Errorf("%w: unknown content type %q", ErrInvalidContent, contentType)
+	}
+}
+
+// normalizeArcana проверяет текст аркана. Ключ - номер аркана 1-22
+func normalizeArcana(key, language string, data json.RawMessage) (json.RawMessage, error) {
+	number, err := strconv.Atoi(key)
+	if err != nil || number < 1 || number > arcana.Count {
+		return nil, fmt.Errorf("%w: arcana key must be a number from 1 to %d", ErrInvalidContent, arcana.Count)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var item models.ArcanaInterpretation
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+	}
+	if strings.TrimSpace(item.Title) == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidContent)
+	}
+
+	item.ArcanaNumber = number
+	item.Language = language
+	return json.Marshal(item)
+}
+
+// normalizePythagoras проверяет тексты элемента психоматрицы.
+// Ключ имеет вид <element>:<key>, например cell:character или row:family
+func normalizePythagoras(key, language string, data json.RawMessage) (json.RawMessage, error) {
+	element, elementKey, _ := strings.Cut(key, ":")
+
+	known := false
+	for _, set := range calculator.BuiltinPythagorasTexts(i18n.DefaultLanguage) {
+		if set.Element == element && set.Key == elementKey {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: unknown pythagoras element %q", ErrInvalidContent, key)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var item models.PythagorasElementInterpretation
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+	}
+	if strings.TrimSpace(item.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidContent)
+	}
+
+	levels := calculator.PythagorasLevels(element)
+	for _, level := range levels {
+		if strings.TrimSpace(item.Texts[level]) == "" {
+			return nil, fmt.Errorf("%w: text for level %q is required", ErrInvalidContent, level)
+		}
+	}
+	if len(item.Texts) != len(levels) {
+		return nil, fmt.Errorf("%w: expected levels %s", ErrInvalidContent, strings.Join(levels, ", "))
+	}
+
+	item.Element = element
+	item.Key = elementKey
+	item.Language = language
+	return json.Marshal(item)
+}
+
+// normalizeCompatibility проверяет текст совместимости. Ключ - ключ сообщения
+// каталога без префикса compatibility., например strength.spiritual. Текст
+// должен содержать те же параметры, что и встроенный
+func normalizeCompatibility(key, language string, data json.RawMessage) (json.RawMessage, error) {
+	bundle := i18n.Default()
+	if !bundle.Has(CompatibilityTextPrefix + key) {
+		return nil, fmt.Errorf("%w: unknown compatibility text %q", ErrInvalidContent, key)
+	}
+	if !i18n.IsSupported(language) {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidContent, language)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var item models.CompatibilityText
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+	}
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" {
+		return nil, fmt.Errorf("%w: text is required", ErrInvalidContent)
+	}
+
+	expected := placeholders(bundle.Message(language, CompatibilityTextPrefix+key))
+	if got := placeholders(item.Text); got != expected {
+		return nil, fmt.Errorf("%w: text must use parameters [%s], got [%s]", ErrInvalidContent, expected, got)
+	}
+
+	item.Key = key
+	item.Language = language
+	return json.Marshal(item)
+}
+
+// placeholders возвращает отсортированный список параметров сообщения
+func placeholders(msg string) string {
+	found := placeholderPattern.FindAllString(msg, -1)
+	sort.Strings(found)
+	unique := found[:0]
+	for i, p := range found {
+		if i == 0 || p != found[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	return strings.Join(unique, ", ")
+}
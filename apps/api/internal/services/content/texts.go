@@ -0,0 +1,106 @@
+package content
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"arcanum/internal/database"
+	"arcanum/internal/i18n"
+)
+
+// textsRetryInterval - как часто повторять загрузку, если база или Redis недоступны
+const textsRetryInterval = time.Minute
+
+// Texts подставляет опубликованные в CMS тексты психоматрицы и совместимости
+// в каталоги сообщений, которыми локализуются результаты расчетов. Каталоги
+// хранятся в памяти и перезагружаются, когда меняется поколение кэша контента
+// (каждая публикация) или истекает cacheTTL. Если опубликованных текстов нет
+// или база недоступна, используются встроенные тексты
+type Texts struct {
+	content *database.ContentRepository
+	cache   *Cache
+
+	mu       sync.Mutex
+	bundle   *i18n.Bundle
+	version  string
+	loadedAt time.Time
+	retryAt  time.Time
+}
+
+func NewTexts(contentRepo *database.ContentRepository, cache *Cache) *Texts {
+	return &Texts{
+		content: contentRepo,
+		cache:   cache,
+	}
+}
+
+// Bundle возвращает каталоги сообщений с опубликованными текстами
+func (t *Texts) Bundle(ctx context.Context) *i18n.Bundle {
+	version, versionErr := t.cache.Version(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.bundle != nil {
+		if now.Before(t.retryAt) {
+			return t.bundle
+		}
+		if versionErr == nil && version == t.version && now.Sub(t.loadedAt) < cacheTTL {
+			return t.bundle
+		}
+	}
+
+	// Если база или Redis недоступны, загрузка повторяется не чаще textsRetryInterval
+	bundle, err := t.load(ctx)
+	if err != nil || versionErr != nil {
+		t.retryAt = now.Add(textsRetryInterval)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to load published texts: %v", err)
+		if t.bundle == nil {
+			t.bundle = i18n.Default()
+		}
+		return t.bundle
+	}
+
+	t.bundle, t.version, t.loadedAt = bundle, version, now
+	return t.bundle
+}
+
+// load читает опубликованные тексты и накладывает их на встроенные каталоги
+func (t *Texts) load(ctx context.Context) (*i18n.Bundle, error) {
+	messages := make(map[string]map[string]string)
+	set := func(lang, key, msg string) {
+		if messages[lang] == nil {
+			messages[lang] = make(map[string]string)
+		}
+		messages[lang][key] = msg
+	}
+
+	for _, lang := range i18n.Languages() {
+		items, err := t.content.ListPythagoras(ctx, lang)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			prefix := "pythagoras." + item.Element + "." + item.Key + "."
+			set(lang, prefix+"name", item.Name)
+			for level, text := range item.Texts {
+				set(lang, prefix+level, text)
+			}
+		}
+	}
+
+	texts, err := t.content.ListCompatibilityTexts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range texts {
+		set(item.Language, CompatibilityTextPrefix+item.Key, item.Text)
+	}
+
+	return i18n.Default().Overlay(messages), nil
+}
@@ -0,0 +1,406 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Условия правил записываются выражениями вида:
+//
+//	rows[0] between 3 and 4
+//	karmicTask in [7, 11, 17]
+//	person1.tail == person2.main or person2.tail == person1.main
+//	not (averageScore >= 80) and towerPairs > 0
+//
+// Операнды - числа и имена фактов. Поддерживаются операторы ==, !=, <, <=, >, >=,
+// between X and Y (включительно), in [...], not in [...], а также and, or, not и скобки
+
+// condition - разобранное условие правила
+type condition interface {
+	eval(env map[string]float64) bool
+	variables() []string
+}
+
+// operand - число или ссылка на факт
+type operand struct {
+	name  string
+	value float64
+}
+
+func (o operand) get(env map[string]float64) float64 {
+	if o.name == "" {
+		return o.value
+	}
+	return env[o.name]
+}
+
+func (o operand) variables() []string {
+	if o.name == "" {
+		return nil
+	}
+	return []string{o.name}
+}
+
+type alwaysCond struct{}
+
+func (alwaysCond) eval(map[string]float64) bool { return true }
+func (alwaysCond) variables() []string          { return nil }
+
+type andCond struct{ left, right condition }
+
+func (c andCond) eval(env map[string]float64) bool { return c.left.eval(env) && c.right.eval(env) }
+func (c andCond) variables() []string {
+	return append(c.left.variables(), c.right.variables()...)
+}
+
+type orCond struct{ left, right condition }
+
+func (c orCond) eval(env map[string]float64) bool { return c.left.eval(env) || c.right.eval(env) }
+func (c orCond) variables() []string {
+	return append(c.left.variables(), c.right.variables()...)
+}
+
+type notCond struct{ inner condition }
+
+func (c notCond) eval(env map[string]float64) bool { return !c.inner.eval(env) }
+func (c notCond) variables() []string              { return c.inner.variables() }
+
+type compareCond struct {
+	left, right operand
+	op          string
+}
+
+func (c compareCond) eval(env map[string]float64) bool {
+	l, r := c.left.get(env), c.right.get(env)
+	switch c.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+func (c compareCond) variables() []string {
+	return append(c.left.variables(), c.right.variables()...)
+}
+
+type betweenCond struct{ value, low, high operand }
+
+func (c betweenCond) eval(env map[string]float64) bool {
+	v := c.value.get(env)
+	return v >= c.low.get(env) && v <= c.high.get(env)
+}
+
+func (c betweenCond) variables() []string {
+	vars := append(c.value.variables(), c.low.variables()...)
+	return append(vars, c.high.variables()...)
+}
+
+type inCond struct {
+	value  operand
+	list   []float64
+	negate bool
+}
+
+func (c inCond) eval(env map[string]float64) bool {
+	v := c.value.get(env)
+	for _, item := range c.list {
+		if v == item {
+			return !c.negate
+		}
+	}
+	return c.negate
+}
+
+func (c inCond) variables() []string { return c.value.variables() }
+
+// parseCondition разбирает выражение условия. Пустое выражение выполняется всегда
+func parseCondition(expr string) (condition, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return alwaysCond{}, nil
+	}
+
+	p := &parser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek())
+	}
+	return cond, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) expect(token string) error {
+	if t := p.next(); t != token {
+		if t == "" {
+			return fmt.Errorf("expected %q at end of expression", token)
+		}
+		return fmt.Errorf("expected %q, got %q", token, t)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCond{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andCond{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (condition, error) {
+	switch p.peek() {
+	case "not":
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCond{inner}, nil
+	case "(":
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *parser) parseComparison() (condition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op := p.next(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareCond{left: left, right: right, op: op}, nil
+	case "between":
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("and"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return betweenCond{value: left, low: low, high: high}, nil
+	case "in":
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inCond{value: left, list: list}, nil
+	case "not":
+		if err := p.expect("in"); err != nil {
+			return nil, err
+		}
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inCond{value: left, list: list, negate: true}, nil
+	case "":
+		return nil, fmt.Errorf("expected operator after %q", p.tokens[p.pos-2])
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return operand{}, fmt.Errorf("unexpected end of expression")
+	case isNumber(t):
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return operand{}, fmt.Errorf("invalid number %q", t)
+		}
+		return operand{value: v}, nil
+	case isIdentifier(t) && !isKeyword(t):
+		return operand{name: t}, nil
+	default:
+		return operand{}, fmt.Errorf("unexpected %q", t)
+	}
+}
+
+func (p *parser) parseList() ([]float64, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+
+	var list []float64
+	for {
+		t := p.next()
+		if !isNumber(t) {
+			return nil, fmt.Errorf("expected number in list, got %q", t)
+		}
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t)
+		}
+		list = append(list, v)
+
+		switch p.next() {
+		case ",":
+			continue
+		case "]":
+			return list, nil
+		default:
+			return nil, fmt.Errorf("expected \",\" or \"]\" in list")
+		}
+	}
+}
+
+// tokenize разбивает выражение на лексемы. Индекс после имени (rows[0])
+// входит в имя факта
+func tokenize(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && runes[i] == '[' {
+				end := i + 1
+				for end < len(runes) && unicode.IsDigit(runes[end]) {
+					end++
+				}
+				if end == i+1 || end >= len(runes) || runes[end] != ']' {
+					return nil, fmt.Errorf("invalid index in %q", string(runes[start:i]))
+				}
+				i = end + 1
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case strings.ContainsRune("<>=!", r):
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+				continue
+			}
+			if r == '=' || r == '!' {
+				return nil, fmt.Errorf("unknown operator %q", string(r))
+			}
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q", string(r))
+		}
+	}
+
+	return tokens, nil
+}
+
+func isNumber(t string) bool {
+	if t == "" {
+		return false
+	}
+	r := []rune(t)[0]
+	return unicode.IsDigit(r) || r == '-'
+}
+
+func isIdentifier(t string) bool {
+	if t == "" {
+		return false
+	}
+	r := []rune(t)[0]
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isKeyword(t string) bool {
+	switch t {
+	case "and", "or", "not", "between", "in":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,442 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"arcanum/internal/i18n"
+)
+
+// Facts - числовые факты, на которых вычисляются условия правил.
+// Логические факты передаются как 1 и 0
+type Facts map[string]float64
+
+// Set сохраняет целочисленный факт
+func (f Facts) Set(name string, value int) {
+	f[name] = float64(value)
+}
+
+// SetBool сохраняет логический факт как 1 или 0
+func (f Facts) SetBool(name string, value bool) {
+	if value {
+		f[name] = 1
+		return
+	}
+	f[name] = 0
+}
+
+// SetList сохраняет список как индексированные факты name[0], name[1], ...
+func (f Facts) SetList(name string, values []int) {
+	for i, v := range values {
+		f[fmt.Sprintf("%s[%d]", name, i)] = float64(v)
+	}
+}
+
+// Spec описывает контракт набора правил с калькулятором: какие факты и
+// текстовые параметры калькулятор передает и какие результаты ожидает
+type Spec struct {
+	// Facts - имена фактов, доступных условиям
+	Facts []string
+	// Params - имена текстовых параметров, доступных сообщениям через param(name)
+	Params []string
+	// Numbers - обязательные числовые значения
+	Numbers []string
+	// Labels - обязательные строковые значения и допустимые для них метки
+	Labels map[string][]string
+	// Texts - списки сообщений, которые читает калькулятор
+	Texts []string
+}
+
+// File - формат файла правил
+type File struct {
+	Description string                `json:"description,omitempty"`
+	Values      []ValueRule           `json:"values,omitempty"`
+	Texts       map[string][]TextRule `json:"texts,omitempty"`
+}
+
+// ValueRule вычисляет значение по первому выполненному условию.
+// Числовые значения становятся фактами для следующих правил
+type ValueRule struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Cases       []Case          `json:"cases"`
+	Default     json.RawMessage `json:"default"`
+}
+
+// Case - вариант значения с условием
+type Case struct {
+	When  string          `json:"when"`
+	Value json.RawMessage `json:"value"`
+}
+
+// TextRule добавляет сообщение в список, если выполнено условие.
+// Параметры сообщения задаются выражениями: имя факта, arcana(факт) -
+// название аркана, param(имя) - текстовый параметр калькулятора
+type TextRule struct {
+	When   string            `json:"when,omitempty"`
+	Key    string            `json:"key"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// RuleSet - проверенный набор правил, готовый к вычислению
+type RuleSet struct {
+	name   string
+	values []*value
+	texts  map[string][]*text
+}
+
+type value struct {
+	name     string
+	label    bool
+	cases    []valueCase
+	fallback valueCase
+}
+
+type valueCase struct {
+	when   condition
+	source string
+	number float64
+	label  string
+}
+
+type text struct {
+	when   condition
+	key    string
+	params map[string]paramExpr
+}
+
+type paramExpr struct {
+	kind string // fact, arcana, param
+	name string
+}
+
+// Load разбирает и проверяет набор правил по контракту spec. Проверяются
+// синтаксис условий, ссылки на факты и параметры, наличие ключей сообщений
+// в каталогах и значения меток
+func Load(name string, data []byte, spec Spec) (*RuleSet, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var file File
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse rules: %w", name, err)
+	}
+
+	set, err := compile(name, &file, spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return set, nil
+}
+
+func compile(name string, file *File, spec Spec) (*RuleSet, error) {
+	set := &RuleSet{name: name, texts: make(map[string][]*text, len(file.Texts))}
+
+	known := make(map[string]bool, len(spec.Facts))
+	for _, f := range spec.Facts {
+		known[f] = true
+	}
+	params := make(map[string]bool, len(spec.Params))
+	for _, p := range spec.Params {
+		params[p] = true
+	}
+
+	defined := make(map[string]bool, len(file.Values))
+	for i, rule := range file.Values {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("values[%d]: name is required", i)
+		}
+		if defined[rule.Name] || known[rule.Name] {
+			return nil, fmt.Errorf("value %q: name is already defined", rule.Name)
+		}
+
+		allowed, isLabel := spec.Labels[rule.Name]
+		v := &value{name: rule.Name, label: isLabel}
+
+		for j, c := range rule.Cases {
+			when, err := compileCondition(c.When, known)
+			if err != nil {
+				return nil, fmt.Errorf("value %q case %d: %w", rule.Name, j+1, err)
+			}
+			vc, err := parseValue(c.Value, isLabel, allowed)
+			if err != nil {
+				return nil, fmt.Errorf("value %q case %d: %w", rule.Name, j+1, err)
+			}
+			vc.when = when
+			vc.source = c.When
+			v.cases = append(v.cases, vc)
+		}
+
+		if len(rule.Default) == 0 {
+			return nil, fmt.Errorf("value %q: default is required", rule.Name)
+		}
+		fallback, err := parseValue(rule.Default, isLabel, allowed)
+		if err != nil {
+			return nil, fmt.Errorf("value %q default: %w", rule.Name, err)
+		}
+		v.fallback = fallback
+
+		defined[rule.Name] = true
+		if !isLabel {
+			known[rule.Name] = true
+		}
+		set.values = append(set.values, v)
+	}
+
+	for _, n := range spec.Numbers {
+		if !defined[n] {
+			return nil, fmt.Errorf("value %q is not defined", n)
+		}
+	}
+	for n := range spec.Labels {
+		if !defined[n] {
+			return nil, fmt.Errorf("value %q is not defined", n)
+		}
+	}
+
+	lists := make(map[string]bool, len(spec.Texts))
+	for _, l := range spec.Texts {
+		lists[l] = true
+	}
+
+	bundle := i18n.Default()
+	for list, rules := range file.Texts {
+		if !lists[list] {
+			return nil, fmt.Errorf("unknown text list %q", list)
+		}
+
+		compiled := make([]*text, 0, len(rules))
+		for i, rule := range rules {
+			if !bundle.Has(rule.Key) {
+				return nil, fmt.Errorf("texts %q rule %d: unknown message key %q", list, i+1, rule.Key)
+			}
+			when, err := compileCondition(rule.When, known)
+			if err != nil {
+				return nil, fmt.Errorf("texts %q rule %d: %w", list, i+1, err)
+			}
+
+			t := &text{when: when, key: rule.Key, params: make(map[string]paramExpr, len(rule.Params))}
+			for p, expr := range rule.Params {
+				pe, err := parseParam(expr, known, params)
+				if err != nil {
+					return nil, fmt.Errorf("texts %q rule %d param %q: %w", list, i+1, p, err)
+				}
+				t.params[p] = pe
+			}
+			compiled = append(compiled, t)
+		}
+		set.texts[list] = compiled
+	}
+
+	return set, nil
+}
+
+// compileCondition разбирает условие и проверяет, что все его факты известны
+func compileCondition(expr string, known map[string]bool) (condition, error) {
+	cond, err := parseCondition(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	for _, v := range cond.variables() {
+		if !known[v] {
+			return nil, fmt.Errorf("condition %q: unknown fact %q", expr, v)
+		}
+	}
+	return cond, nil
+}
+
+// parseValue разбирает значение варианта: число или метку из списка допустимых
+func parseValue(raw json.RawMessage, isLabel bool, allowed []string) (valueCase, error) {
+	if isLabel {
+		var label string
+		if err := json.Unmarshal(raw, &label); err != nil {
+			return valueCase{}, fmt.Errorf("expected label, got %s", string(raw))
+		}
+		for _, a := range allowed {
+			if a == label {
+				return valueCase{label: label}, nil
+			}
+		}
+		return valueCase{}, fmt.Errorf("label %q is not one of %s", label, strings.Join(allowed, ", "))
+	}
+
+	var number float64
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return valueCase{}, fmt.Errorf("expected number, got %s", string(raw))
+	}
+	return valueCase{number: number}, nil
+}
+
+// parseParam разбирает выражение параметра сообщения
+func parseParam(expr string, known, params map[string]bool) (paramExpr, error) {
+	expr = strings.TrimSpace(expr)
+	for _, kind := range []string{"arcana", "param"} {
+		if strings.HasPrefix(expr, kind+"(") && strings.HasSuffix(expr, ")") {
+			name := strings.TrimSpace(expr[len(kind)+1 : len(expr)-1])
+			if kind == "param" && !params[name] {
+				return paramExpr{}, fmt.Errorf("unknown parameter %q", name)
+			}
+			if kind == "arcana" && !known[name] {
+				return paramExpr{}, fmt.Errorf("unknown fact %q", name)
+			}
+			return paramExpr{kind: kind, name: name}, nil
+		}
+	}
+	if !known[expr] {
+		return paramExpr{}, fmt.Errorf("unknown fact %q", expr)
+	}
+	return paramExpr{kind: "fact", name: expr}, nil
+}
+
+// Name возвращает имя набора правил
+func (s *RuleSet) Name() string {
+	return s.name
+}
+
+// Evaluate вычисляет правила на фактах. params - текстовые параметры,
+// доступные сообщениям через param(name). Отсутствующие факты считаются равными 0
+func (s *RuleSet) Evaluate(facts Facts, params i18n.Params) *Result {
+	env := make(map[string]float64, len(facts)+len(s.values))
+	for k, v := range facts {
+		env[k] = v
+	}
+
+	result := &Result{
+		numbers: make(map[string]float64, len(s.values)),
+		labels:  make(map[string]string),
+		texts:   make(map[string][]i18n.Text, len(s.texts)),
+		matches: make(map[string]Match, len(s.values)),
+	}
+
+	for _, v := range s.values {
+		matched := v.fallback
+		match := Match{Facts: make(map[string]float64)}
+		for _, c := range v.cases {
+			for _, name := range c.when.variables() {
+				match.Facts[name] = env[name]
+			}
+			if c.when.eval(env) {
+				matched = c
+				match.Condition = c.source
+				break
+			}
+		}
+		result.matches[v.name] = match
+
+		if v.label {
+			result.labels[v.name] = matched.label
+			continue
+		}
+		env[v.name] = matched.number
+		result.numbers[v.name] = matched.number
+	}
+
+	for list, rules := range s.texts {
+		for _, rule := range rules {
+			if !rule.when.eval(env) {
+				continue
+			}
+			result.texts[list] = append(result.texts[list], rule.render(env, params))
+		}
+	}
+
+	return result
+}
+
+func (t *text) render(env map[string]float64, params i18n.Params) i18n.Text {
+	if len(t.params) == 0 {
+		return i18n.T(t.key)
+	}
+
+	rendered := make(i18n.Params, len(t.params))
+	for name, p := range t.params {
+		switch p.kind {
+		case "arcana":
+			rendered[name] = i18n.ArcanaName(int(env[p.name]))
+		case "param":
+			rendered[name] = params[p.name]
+		default:
+			rendered[name] = formatNumber(env[p.name])
+		}
+	}
+	return i18n.T(t.key, rendered)
+}
+
+// formatNumber возвращает целые числа без дробной части
+func formatNumber(v float64) interface{} {
+	if v == math.Trunc(v) {
+		return int(v)
+	}
+	return v
+}
+
+// Result - результат вычисления набора правил
+type Result struct {
+	numbers map[string]float64
+	labels  map[string]string
+	texts   map[string][]i18n.Text
+	matches map[string]Match
+}
+
+// Match описывает, как было выбрано значение
+type Match struct {
+	// Condition - условие сработавшего варианта, пусто для значения по умолчанию
+	Condition string
+	// Facts - значения фактов из проверенных условий
+	Facts map[string]float64
+}
+
+// Number возвращает числовое значение
+func (r *Result) Number(name string) float64 {
+	return r.numbers[name]
+}
+
+// Int возвращает числовое значение как целое
+func (r *Result) Int(name string) int {
+	return int(r.numbers[name])
+}
+
+// Bool возвращает числовое значение как логическое (не 0)
+func (r *Result) Bool(name string) bool {
+	return r.numbers[name] != 0
+}
+
+// Match возвращает сработавший вариант значения
+func (r *Result) Match(name string) Match {
+	return r.matches[name]
+}
+
+// Label возвращает строковое значение
+func (r *Result) Label(name string) string {
+	return r.labels[name]
+}
+
+// Texts возвращает сообщения списка в порядке правил. Пустой список не равен nil
+func (r *Result) Texts(list string) []i18n.Text {
+	texts := make([]i18n.Text, 0, len(r.texts[list]))
+	return append(texts, r.texts[list]...)
+}
+
+// TextLists возвращает все непустые списки сообщений
+func (r *Result) TextLists() map[string][]i18n.Text {
+	lists := make(map[string][]i18n.Text, len(r.texts))
+	for name := range r.texts {
+		lists[name] = r.Texts(name)
+	}
+	return lists
+}
+
+// Values возвращает все вычисленные числовые значения и метки
+func (r *Result) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(r.numbers)+len(r.labels))
+	for n, v := range r.numbers {
+		values[n] = formatNumber(v)
+	}
+	for n, label := range r.labels {
+		values[n] = label
+	}
+	return values
+}
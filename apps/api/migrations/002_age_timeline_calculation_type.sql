@@ -0,0 +1,2 @@
+-- Линия возраста (периоды жизни по периметру матрицы)
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'age_timeline';
@@ -0,0 +1,2 @@
+-- Число Имени (расчет по ФИО)
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'name_number';
@@ -0,0 +1,2 @@
+-- Совместимость группы (семья, команда)
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'group_compatibility';
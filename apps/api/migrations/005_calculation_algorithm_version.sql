@@ -0,0 +1,6 @@
+-- Версия алгоритма, которым получен результат расчета
+ALTER TABLE calculations ADD COLUMN IF NOT EXISTS algorithm_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_calculations_type_version ON calculations(type, algorithm_version);
+
+COMMENT ON COLUMN calculations.algorithm_version IS 'Версия методики расчета, которой получен result_data';
@@ -0,0 +1,27 @@
+-- Локализованные интерпретации арканов для Content API
+CREATE TABLE IF NOT EXISTS arcana_interpretations (
+    id SERIAL PRIMARY KEY,
+    arcana_number INTEGER NOT NULL CHECK (arcana_number BETWEEN 1 AND 22),
+    language VARCHAR(10) NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    short_description TEXT,
+    full_description TEXT,
+    plus_aspects JSONB NOT NULL DEFAULT '[]',
+    minus_aspects JSONB NOT NULL DEFAULT '[]',
+    love_aspect TEXT,
+    money_aspect TEXT,
+    health_aspect TEXT,
+    child_aspect TEXT,
+    recommendations JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(arcana_number, language)
+);
+
+CREATE INDEX IF NOT EXISTS idx_arcana_interpretations_language ON arcana_interpretations(language);
+
+-- Триггер для updated_at
+DROP TRIGGER IF EXISTS update_arcana_interpretations_updated_at ON arcana_interpretations;
+CREATE TRIGGER update_arcana_interpretations_updated_at
+    BEFORE UPDATE ON arcana_interpretations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
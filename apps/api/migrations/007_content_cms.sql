@@ -0,0 +1,70 @@
+-- Роли пользователей: администраторы управляют справочным контентом
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'admin'));
+
+-- Опубликованные интерпретации психоматрицы Пифагора
+CREATE TABLE IF NOT EXISTS pythagoras_interpretations (
+    id SERIAL PRIMARY KEY,
+    element VARCHAR(20) NOT NULL CHECK (element IN ('cell', 'row', 'column', 'diagonal')),
+    element_key VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    texts JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(element, element_key, language)
+);
+
+DROP TRIGGER IF EXISTS update_pythagoras_interpretations_updated_at ON pythagoras_interpretations;
+CREATE TRIGGER update_pythagoras_interpretations_updated_at
+    BEFORE UPDATE ON pythagoras_interpretations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Опубликованные тексты совместимости. Заменяют встроенные тексты с тем же ключом
+CREATE TABLE IF NOT EXISTS compatibility_texts (
+    id SERIAL PRIMARY KEY,
+    text_key VARCHAR(100) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    text TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(text_key, language)
+);
+
+DROP TRIGGER IF EXISTS update_compatibility_texts_updated_at ON compatibility_texts;
+CREATE TRIGGER update_compatibility_texts_updated_at
+    BEFORE UPDATE ON compatibility_texts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Ревизии контента: черновик -> на проверке -> опубликовано
+DO $$
+BEGIN
+    CREATE TYPE content_status AS ENUM ('draft', 'review', 'published', 'archived');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS content_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    content_type VARCHAR(20) NOT NULL CHECK (content_type IN ('arcana', 'pythagoras', 'compatibility')),
+    content_key VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    revision INTEGER NOT NULL,
+    status content_status NOT NULL DEFAULT 'draft',
+    data JSONB NOT NULL,
+    comment TEXT,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(content_type, content_key, language, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_revisions_content ON content_revisions(content_type, content_key, language);
+CREATE INDEX IF NOT EXISTS idx_content_revisions_status ON content_revisions(status) WHERE status = 'review';
+
+-- Не более одной опубликованной ревизии на единицу контента
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_revisions_published
+    ON content_revisions(content_type, content_key, language) WHERE status = 'published';
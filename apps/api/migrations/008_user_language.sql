@@ -0,0 +1,9 @@
+-- Язык пользователя: используется, если клиент не передал Accept-Language
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'ru';
+
+COMMENT ON COLUMN users.language IS 'Язык результатов расчетов по умолчанию (ru, en)';
+
+-- Язык текстов сохраненного результата: аудит пересчитывает расчеты на том же языке
+ALTER TABLE calculations ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'ru';
+
+COMMENT ON COLUMN calculations.language IS 'Язык текстов result_data (ru, en)';
@@ -0,0 +1,6 @@
+-- Дата рождения и часовой пояс пользователя: нужны для персонального аркана дня
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birth_date DATE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+COMMENT ON COLUMN users.birth_date IS 'Дата рождения для персональных расчетов (аркан дня)';
+COMMENT ON COLUMN users.timezone IS 'Часовой пояс IANA, в котором определяется текущая дата пользователя';
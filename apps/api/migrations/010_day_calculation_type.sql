@@ -0,0 +1,2 @@
+-- Энергия дня
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'day';
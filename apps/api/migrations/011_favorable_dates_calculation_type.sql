@@ -0,0 +1,2 @@
+-- Благоприятные даты
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'favorable_dates';
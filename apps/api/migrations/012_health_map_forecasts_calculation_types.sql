@@ -0,0 +1,3 @@
+-- Карта здоровья и прогнозы
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'health_map';
+ALTER TYPE calculation_type ADD VALUE IF NOT EXISTS 'forecasts';
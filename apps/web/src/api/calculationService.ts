@@ -1,6 +1,6 @@
 import apiClient from './apiClient';
 
-export type CalculationType = 'matrix' | 'pythagoras' | 'compatibility' | 'child_role';
+export type CalculationType = 'matrix' | 'pythagoras' | 'compatibility' | 'child_role' | 'age_timeline' | 'name_number' | 'group_compatibility';
 
 export interface Calculation {
     id: string;